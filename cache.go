@@ -0,0 +1,293 @@
+package validate
+
+import (
+	"reflect"
+	"sync"
+)
+
+// typeInfo holds reflection metadata about a type which is needed while
+// validating values of the type. It is computed once per type and Validator,
+// and cached for subsequent validation runs.
+type typeInfo struct {
+	// walk indicates if values of the type may be, or may contain Validatable
+	// values or struct fields with tag rules. Values of types which cannot are
+	// skipped entirely.
+	walk bool
+
+	// fields lists all struct fields which should be walked.
+	fields []fieldInfo
+
+	// byName maps Go field names to their display names resolved via the
+	// Validator's FieldNameFunc and their types, including promoted fields of
+	// embedded structs.
+	byName map[string]namedField
+}
+
+// namedField is the display name and type of a struct field.
+type namedField struct {
+	field string
+	typ   reflect.Type
+}
+
+// fieldInfo describes a single struct field which should be walked.
+type fieldInfo struct {
+	index int
+
+	// name is the Go name of the field.
+	name string
+
+	// field is the display name of the field, as returned by the Validator's
+	// FieldNameFunc.
+	field string
+
+	// walk indicates if the field's value may be, or may contain Validatable
+	// values.
+	walk bool
+
+	// rules holds rules declared in the field's tag.
+	rules []rule
+}
+
+// typeCache is a concurrency-safe cache of typeInfo values.
+type typeCache struct {
+	m sync.Map
+}
+
+func (s *typeCache) load(t reflect.Type) (*typeInfo, bool) {
+	v, ok := s.m.Load(t)
+	if !ok {
+		return nil, false
+	}
+
+	return v.(*typeInfo), true
+}
+
+func (s *typeCache) store(t reflect.Type, info *typeInfo) *typeInfo {
+	v, _ := s.m.LoadOrStore(t, info)
+
+	return v.(*typeInfo)
+}
+
+// reset removes all cached values.
+func (s *typeCache) reset() {
+	s.m.Range(func(k, _ interface{}) bool {
+		s.m.Delete(k)
+
+		return true
+	})
+}
+
+// typeInfo returns cached metadata for the given type, building and caching
+// it if needed. Metadata is rebuilt if rules were registered while building it,
+// as it may refer to replaced rules.
+func (s *Validator) typeInfo(t reflect.Type) *typeInfo {
+	for {
+		if info, ok := s.types.load(t); ok {
+			return info
+		}
+
+		gen := s.rulesGeneration()
+		if info, ok := s.storeTypeInfo(t, s.buildTypeInfo(t), gen); ok {
+			return info
+		}
+	}
+}
+
+// storeTypeInfo caches info for the given type, unless rules were registered
+// since the given generation, in which case false is returned.
+func (s *Validator) storeTypeInfo(
+	t reflect.Type,
+	info *typeInfo,
+	gen uint64,
+) (*typeInfo, bool) {
+	// Holding the read lock keeps RegisterRule from resetting the cache
+	// between the check and the store.
+	s.rulesMu.RLock()
+	defer s.rulesMu.RUnlock()
+
+	if s.rulesGen != gen {
+		return nil, false
+	}
+
+	return s.types.store(t, info), true
+}
+
+// buildTypeInfo builds metadata for the given type.
+func (s *Validator) buildTypeInfo(t reflect.Type) *typeInfo {
+	a := &analysis{Validator: s, stack: map[reflect.Type]bool{}}
+	info := &typeInfo{}
+	info.walk, _ = a.walkable(t)
+
+	if t.Kind() == reflect.Struct {
+		info.byName = map[string]namedField{}
+		for _, f := range s.structFields(t) {
+			f.walk, _ = a.walkable(t.Field(f.index).Type)
+			if f.walk || len(f.rules) > 0 {
+				info.fields = append(info.fields, f)
+			}
+		}
+
+		for _, n := range fieldNames(t, map[reflect.Type]bool{}) {
+			if sf, ok := t.FieldByName(n); ok {
+				info.byName[n] = namedField{
+					field: s.fieldName(sf),
+					typ:   sf.Type,
+				}
+			}
+		}
+	}
+
+	return info
+}
+
+// structFields returns all exported fields of the given struct type which have
+// a non-empty name as returned by the Validator's FieldNameFunc, along with any
+// rules declared in their tags.
+func (s *Validator) structFields(t reflect.Type) []fieldInfo {
+	var fields []fieldInfo
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+
+		if name := s.fieldName(sf); name != "" {
+			fields = append(fields, fieldInfo{
+				index: i,
+				name:  sf.Name,
+				field: name,
+				rules: s.parseRules(sf),
+			})
+		}
+	}
+
+	return fields
+}
+
+var (
+	validatableType            = reflect.TypeOf((*Validatable)(nil)).Elem()
+	validatableWithContextType = reflect.TypeOf(
+		(*ValidatableWithContext)(nil),
+	).Elem()
+)
+
+// implementsValidatable checks if the given type, or a pointer to it,
+// implements Validatable or ValidatableWithContext.
+func implementsValidatable(t reflect.Type) bool {
+	if t.Implements(validatableType) ||
+		t.Implements(validatableWithContextType) {
+		return true
+	}
+
+	if t.Kind() != reflect.Ptr && t.Kind() != reflect.Interface {
+		pt := reflect.PtrTo(t)
+
+		return pt.Implements(validatableType) ||
+			pt.Implements(validatableWithContextType)
+	}
+
+	return false
+}
+
+// analysis statically determines which types may contain Validatable values.
+type analysis struct {
+	*Validator
+
+	// stack holds struct types currently being analyzed, to handle recursive
+	// types.
+	stack map[reflect.Type]bool
+
+	// results holds definitive results determined during the analysis.
+	results map[reflect.Type]bool
+}
+
+// walkable reports if values of the given type may be, or contain Validatable
+// values or struct fields with tag rules. The second return value indicates if
+// the result is tentative, as it relied on the assumption that a recursive type
+// currently being analyzed does not contain Validatable values.
+func (s *analysis) walkable(t reflect.Type) (bool, bool) {
+	if info, ok := s.types.load(t); ok {
+		return info.walk, false
+	}
+
+	if r, ok := s.results[t]; ok {
+		return r, false
+	}
+
+	if implementsValidatable(t) {
+		return true, false
+	}
+
+	switch t.Kind() { //nolint:exhaustive
+	case reflect.Interface:
+		return true, false
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return s.walkable(t.Elem())
+	case reflect.Struct:
+		if s.stack[t] {
+			return false, true
+		}
+
+		s.stack[t] = true
+		defer delete(s.stack, t)
+
+		tentative := false
+		for _, f := range s.structFields(t) {
+			if len(f.rules) > 0 {
+				s.remember(t, true)
+
+				return true, false
+			}
+
+			ok, tent := s.walkable(t.Field(f.index).Type)
+			if ok {
+				s.remember(t, true)
+
+				return true, false
+			}
+			tentative = tentative || tent
+		}
+
+		if !tentative {
+			s.remember(t, false)
+		}
+
+		return false, tentative
+	}
+
+	return false, false
+}
+
+func (s *analysis) remember(t reflect.Type, result bool) {
+	if s.results == nil {
+		s.results = map[reflect.Type]bool{}
+	}
+	s.results[t] = result
+}
+
+// fieldNames returns the names of all fields of the given struct type,
+// including fields of embedded structs at any depth.
+func fieldNames(t reflect.Type, seen map[reflect.Type]bool) []string {
+	if seen[t] {
+		return nil
+	}
+	seen[t] = true
+
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		names = append(names, sf.Name)
+
+		if sf.Anonymous {
+			ft := sf.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				names = append(names, fieldNames(ft, seen)...)
+			}
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,223 @@
+package validate
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testEmbedded struct {
+	Inner string `json:"inner"`
+}
+
+type testEmbeddingStruct struct {
+	*testEmbedded
+	Outer   *testStruct `json:"outer"`
+	Plain   string      `json:"plain"`
+	Skipped *testStruct `json:"-"`
+	hidden  *testStruct
+}
+
+func TestValidator_typeInfo(t *testing.T) {
+	v := New()
+
+	got := v.typeInfo(reflect.TypeOf(testEmbeddingStruct{}))
+
+	assert.Equal(t, []fieldInfo{
+		{index: 1, name: "Outer", field: "outer", walk: true},
+	}, got.fields)
+	testStructPtr := reflect.TypeOf(&testStruct{})
+	assert.Equal(t, map[string]namedField{
+		"testEmbedded": {
+			field: "testEmbedded", typ: reflect.TypeOf(&testEmbedded{}),
+		},
+		"Inner":   {field: "inner", typ: reflect.TypeOf("")},
+		"Outer":   {field: "outer", typ: testStructPtr},
+		"Plain":   {field: "plain", typ: reflect.TypeOf("")},
+		"Skipped": {field: "", typ: testStructPtr},
+		"hidden":  {field: "hidden", typ: testStructPtr},
+	}, got.byName)
+	assert.Same(t, got, v.typeInfo(reflect.TypeOf(testEmbeddingStruct{})))
+}
+
+type testValueReceiver struct{}
+
+func (testValueReceiver) Validate() error { return nil }
+
+type testRecursive struct {
+	Next  *testRecursive
+	Other *testRecursiveOther
+	Items []testRecursive
+}
+
+type testRecursiveOther struct {
+	Back *testRecursive
+}
+
+type testRecursiveValidatable struct {
+	Other *testRecursiveValidatableOther
+	Value *testStruct
+}
+
+type testRecursiveValidatableOther struct {
+	Back *testRecursiveValidatable
+}
+
+func TestValidator_typeInfo_walk(t *testing.T) {
+	tests := []struct {
+		name string
+		obj  interface{}
+		want bool
+	}{
+		{name: "string", obj: "", want: false},
+		{name: "byte slice", obj: []byte{}, want: false},
+		{name: "float slice", obj: []float64{}, want: false},
+		{name: "string map", obj: map[string]string{}, want: false},
+		{name: "int array", obj: [4]int{}, want: false},
+		{name: "nested slices", obj: [][]*int{}, want: false},
+		{name: "interface slice", obj: []interface{}{}, want: true},
+		{name: "pointer receiver", obj: testStruct{}, want: true},
+		{name: "value receiver", obj: testValueReceiver{}, want: true},
+		{
+			name: "value receiver map",
+			obj:  map[int]testValueReceiver{},
+			want: true,
+		},
+		{name: "pointer receiver slice", obj: []testStruct{}, want: true},
+		{name: "context", obj: &testContextStruct{}, want: true},
+		{name: "plain struct", obj: struct{ Name string }{}, want: false},
+		{
+			name: "struct with skipped fields",
+			obj: struct {
+				Foo *testStruct `json:"-"`
+				bar *testStruct
+			}{},
+			want: false,
+		},
+		{name: "recursive", obj: testRecursive{}, want: false},
+		{name: "recursive other", obj: testRecursiveOther{}, want: false},
+		{
+			name: "recursive validatable",
+			obj:  testRecursiveValidatable{},
+			want: true,
+		},
+		{
+			name: "recursive validatable other",
+			obj:  testRecursiveValidatableOther{},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := New()
+
+			got := v.typeInfo(reflect.TypeOf(tt.obj))
+
+			assert.Equal(t, tt.want, got.walk)
+		})
+	}
+}
+
+func TestValidator_typeInfo_walkRecursiveOrder(t *testing.T) {
+	v := New()
+
+	// Analyzing the outer type first must not cause the inner type which
+	// refers back to it to be incorrectly cached as not walkable.
+	outer := v.typeInfo(reflect.TypeOf(testRecursiveValidatable{}))
+	other := v.typeInfo(reflect.TypeOf(testRecursiveValidatableOther{}))
+
+	assert.True(t, outer.walk)
+	assert.True(t, other.walk)
+}
+
+func TestValidator_typeInfo_resetByFieldNameFunc(t *testing.T) {
+	v := New()
+	typ := reflect.TypeOf(testEmbeddingStruct{})
+	before := v.typeInfo(typ)
+
+	v.FieldNameFunc(func(sf reflect.StructField) string { return sf.Name })
+	got := v.typeInfo(typ)
+
+	assert.NotSame(t, before, got)
+	assert.Equal(t, "Outer", got.byName["Outer"].field)
+	assert.Equal(t, []fieldInfo{
+		{index: 1, name: "Outer", field: "Outer", walk: true},
+		{index: 3, name: "Skipped", field: "Skipped", walk: true},
+	}, got.fields)
+}
+
+type benchOrder struct {
+	ID    string       `json:"id"`
+	Items []*benchItem `json:"items"`
+	Notes []string     `json:"notes"`
+}
+
+type benchItem struct {
+	SKU      string            `json:"sku"`
+	Quantity int               `json:"quantity"`
+	Price    float64           `json:"price"`
+	Labels   map[string]string `json:"labels"`
+}
+
+func (s *benchItem) Validate() error {
+	var errs error
+	errs = Append(errs, RequireField("SKU", s.SKU))
+	errs = Append(errs, RequireField("Quantity", s.Quantity))
+
+	return errs
+}
+
+func newBenchOrder() *benchOrder {
+	order := &benchOrder{ID: "order-1"}
+	for i := 0; i < 50; i++ {
+		order.Items = append(order.Items, &benchItem{
+			SKU:      "sku",
+			Quantity: 1,
+			Price:    9.99,
+			Labels:   map[string]string{"color": "red"},
+		})
+		order.Notes = append(order.Notes, "note")
+	}
+
+	return order
+}
+
+func BenchmarkValidator_Validate(b *testing.B) {
+	order := newBenchOrder()
+
+	b.Run("cached", func(b *testing.B) {
+		v := New()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_ = v.Validate(order)
+		}
+	})
+
+	b.Run("uncached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = New().Validate(order)
+		}
+	})
+}
+
+func BenchmarkValidator_Validate_largeSlices(b *testing.B) {
+	obj := &struct {
+		Data    []byte
+		Samples []float64
+		Item    *benchItem
+	}{
+		Data:    make([]byte, 10<<20),
+		Samples: make([]float64, 1_000_000),
+		Item:    &benchItem{SKU: "sku", Quantity: 1},
+	}
+	v := New()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = v.Validate(obj)
+	}
+}
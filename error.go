@@ -1,36 +1,230 @@
 package validate
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 
 	"go.uber.org/multierr"
 )
 
+// ErrCycle is wrapped by errors reported for reference cycles, when cycle
+// reporting is enabled on a Validator.
+var ErrCycle = errors.New("reference cycle detected")
+
+// ErrNotAddressable is wrapped by errors reported in strict mode for values
+// which implement Validatable or ValidatableWithContext only via a pointer
+// receiver, but which are not addressable, like map values.
+var ErrNotAddressable = errors.New(
+	"validate method has pointer receiver, but value is not addressable",
+)
+
+// Error codes set by RequireField, other helpers, and built-in tag rules.
+// Codes are stable, and intended to be matched on by API clients, unlike Msg
+// which may change.
+const (
+	CodeRequired        = "required"
+	CodeNotEmpty        = "not_empty"
+	CodeTooShort        = "too_short"
+	CodeTooLong         = "too_long"
+	CodeTooFewItems     = "too_few_items"
+	CodeTooManyItems    = "too_many_items"
+	CodeTooSmall        = "too_small"
+	CodeTooLarge        = "too_large"
+	CodeNotOneOf        = "not_one_of"
+	CodePatternMismatch = "pattern_mismatch"
+	CodeMissingPrefix   = "missing_prefix"
+	CodeMissingSuffix   = "missing_suffix"
+	CodeDuplicateItems  = "duplicate_items"
+	CodeNegative        = "negative"
+	CodeInvalidFormat   = "invalid_format"
+
+	CodeNotEqualField          = "not_equal_field"
+	CodeEqualField             = "equal_field"
+	CodeNotLessField           = "not_less_field"
+	CodeNotLessOrEqualField    = "not_less_or_equal_field"
+	CodeNotGreaterField        = "not_greater_field"
+	CodeNotGreaterOrEqualField = "not_greater_or_equal_field"
+)
+
+// FieldRef is a reference to another field, used as a value in the Params of
+// errors which relate to more than one field, like those returned by
+// EqualField. It holds the Go name of the field, relative to the object whose
+// Validate method returned the error, in the same format as the Field of a
+// *Error. When reported by a Validator, FieldRef params are replaced with the
+// display name of the referenced field as a string.
+type FieldRef string
+
+// Severity indicates how serious a reported Error is.
+type Severity int
+
+const (
+	// SeverityError is the default severity, for errors which cause
+	// validation to fail.
+	SeverityError Severity = iota
+
+	// SeverityWarning is for problems which do not cause validation to fail,
+	// like the use of deprecated settings.
+	SeverityWarning
+
+	// SeverityInfo is for informational notices which do not cause validation
+	// to fail.
+	SeverityInfo
+)
+
+// String returns a textual representation of the severity.
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "info"
+	}
+
+	return "unknown"
+}
+
+// MarshalText implements encoding.TextMarshaler, encoding the severity as its
+// textual representation.
+func (s Severity) MarshalText() ([]byte, error) {
+	switch s {
+	case SeverityError, SeverityWarning, SeverityInfo:
+		return []byte(s.String()), nil
+	}
+
+	return nil, fmt.Errorf("validate: invalid severity %d", int(s))
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, decoding severities
+// encoded by MarshalText.
+func (s *Severity) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "error":
+		*s = SeverityError
+	case "warning":
+		*s = SeverityWarning
+	case "info":
+		*s = SeverityInfo
+	default:
+		return fmt.Errorf("validate: invalid severity %q", text)
+	}
+
+	return nil
+}
+
 // Error represents validation errors, and implements Go's error type. Field
 // indicates the struct field the validation error is relevant to, which is the
 // full nested path relative to the top-level object being validated.
+//
+// Path holds the same nested path as Field, as a list of structured segments.
+// It is populated for all errors returned by Validator, and allows telling
+// apart struct fields, slice indexes, and map keys, without needing to parse
+// Field.
+//
+// Code is a optional machine-readable identifier of the kind of validation
+// failure, like "required" or "too_short", and Params holds the parameters of
+// the failed constraint, like {"min": 3}. Both are set by all built-in helpers
+// and tag rules, and preserved when errors are returned from Validate methods.
+//
+// Severity defaults to SeverityError. Errors with any other severity are
+// warnings, which do not cause validation to fail. They are omitted from the
+// errors returned by Validate, and can be retrieved with Check instead.
 type Error struct {
-	Field string
-	Msg   string
-	Err   error
+	Field    string
+	Path     []PathSegment
+	Code     string
+	Params   map[string]interface{}
+	Severity Severity
+	Msg      string
+	Err      error
 }
 
 func (s *Error) Error() string {
-	msg := s.Msg
-	if msg == "" && s.Err != nil {
-		msg = s.Err.Error()
+	msg := s.message()
+	if s.Field == "" {
+		return msg
 	}
 
-	if msg == "" {
-		msg = "unknown error"
+	return fmt.Sprintf("%s: %s", s.Field, msg)
+}
+
+// message returns the error message without the field, falling back to the
+// message of the wrapped error.
+func (s *Error) message() string {
+	if s.Msg != "" {
+		return s.Msg
 	}
 
-	if s.Field == "" {
-		return msg
+	if s.Err != nil {
+		return s.Err.Error()
 	}
 
-	return fmt.Sprintf("%s: %s", s.Field, msg)
+	return "unknown error"
+}
+
+// jsonError is the JSON representation of a Error.
+type jsonError struct {
+	Field    string                 `json:"field,omitempty"`
+	Path     []PathSegment          `json:"path,omitempty"`
+	Code     string                 `json:"code,omitempty"`
+	Params   map[string]interface{} `json:"params,omitempty"`
+	Severity Severity               `json:"severity,omitempty"`
+	Message  string                 `json:"message,omitempty"`
+	Error    string                 `json:"error,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler. The wrapped Err is encoded as its
+// message, and Severity is omitted for SeverityError. It has a value receiver,
+// so Error values and structs embedding them are encoded the same way.
+func (s Error) MarshalJSON() ([]byte, error) {
+	j := jsonError{
+		Field:    s.Field,
+		Path:     s.Path,
+		Code:     s.Code,
+		Params:   s.Params,
+		Severity: s.Severity,
+		Message:  s.Msg,
+	}
+	if s.Err != nil {
+		j.Error = s.Err.Error()
+	}
+
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, decoding errors encoded by
+// MarshalJSON. Wrapped errors matching ErrCycle or ErrNotAddressable are
+// restored as the respective sentinel error, so they can be checked with
+// errors.Is. Other wrapped errors are restored as new errors with the same
+// message. Numeric params are decoded as float64.
+func (s *Error) UnmarshalJSON(data []byte) error {
+	var j jsonError
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	*s = Error{
+		Field:    j.Field,
+		Path:     j.Path,
+		Code:     j.Code,
+		Params:   j.Params,
+		Severity: j.Severity,
+		Msg:      j.Message,
+	}
+
+	switch j.Error {
+	case "":
+	case ErrCycle.Error():
+		s.Err = ErrCycle
+	case ErrNotAddressable.Error():
+		s.Err = ErrNotAddressable
+	default:
+		s.Err = errors.New(j.Error)
+	}
+
+	return nil
 }
 
 func (s *Error) Is(target error) bool {
@@ -61,7 +255,53 @@ func AppendFieldError(errs error, field, msg string) error {
 	return multierr.Append(errs, &Error{Field: field, Msg: msg})
 }
 
+// AppendFieldWarning appends a new *Error type to errs with Field and Msg
+// populated with given field and msg values, and Severity set to
+// SeverityWarning.
+func AppendFieldWarning(errs error, field, msg string) error {
+	return multierr.Append(errs, &Error{
+		Field: field, Severity: SeverityWarning, Msg: msg,
+	})
+}
+
 // Errors returns a slice of all errors appended into the given error.
 func Errors(err error) []error {
 	return multierr.Errors(err)
 }
+
+// ErrorList is a list of *Error values, which can be encoded to and decoded
+// from a JSON array, allowing validation results to be passed across service
+// boundaries.
+type ErrorList []*Error
+
+// NewErrorList returns a ErrorList of all errors within err, as returned by
+// Validate. Errors which are not a *Error are wrapped in one. It returns nil if
+// err is nil.
+func NewErrorList(err error) ErrorList {
+	errs := Errors(err)
+	if len(errs) == 0 {
+		return nil
+	}
+
+	list := make(ErrorList, 0, len(errs))
+	for _, e := range errs {
+		vErr, ok := e.(*Error) //nolint:errorlint
+		if !ok {
+			vErr = &Error{Err: e}
+		}
+		list = append(list, vErr)
+	}
+
+	return list
+}
+
+// Err returns all errors in the list combined into a single error in the same
+// way as Validate, or nil if the list is empty.
+func (s ErrorList) Err() error {
+	var errs error
+	for _, err := range s {
+		errs = multierr.Append(errs, err)
+	}
+
+	return errs
+}
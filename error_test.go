@@ -1,10 +1,12 @@
 package validate
 
 import (
+	"encoding/json"
 	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/multierr"
 )
 
@@ -407,6 +409,29 @@ func TestAppendFieldError(t *testing.T) {
 	}
 }
 
+func TestAppendFieldWarning(t *testing.T) {
+	errs := AppendFieldError(nil, "Name", "is required")
+	errs = AppendFieldWarning(errs, "Tag", "is deprecated")
+
+	assert.Equal(t, []error{
+		&Error{Field: "Name", Msg: "is required"},
+		&Error{Field: "Tag", Severity: SeverityWarning, Msg: "is deprecated"},
+	}, Errors(errs))
+}
+
+func TestSeverity_String(t *testing.T) {
+	assert.Equal(t, "error", SeverityError.String())
+	assert.Equal(t, "warning", SeverityWarning.String())
+	assert.Equal(t, "info", SeverityInfo.String())
+	assert.Equal(t, "unknown", Severity(9).String())
+}
+
+func TestError_MarshalJSON_invalidSeverity(t *testing.T) {
+	_, err := json.Marshal(&Error{Severity: 9})
+
+	assert.Error(t, err)
+}
+
 func TestErrors(t *testing.T) {
 	type args struct {
 		err error
@@ -454,3 +479,225 @@ func TestErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestError_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *Error
+		want string
+	}{
+		{
+			name: "empty",
+			err:  &Error{},
+			want: `{}`,
+		},
+		{
+			name: "all fields",
+			err: &Error{
+				Field: "items.a.name",
+				Path: []PathSegment{
+					{Kind: FieldSegment, Name: "Items", Field: "items"},
+					{Kind: KeySegment, Key: "a"},
+					{Kind: IndexSegment, Index: 0},
+				},
+				Code:   CodeTooShort,
+				Params: map[string]interface{}{"min": 3},
+				Msg:    "must be at least 3 characters long",
+				Err:    errors.New("oops"),
+			},
+			want: `{
+				"field": "items.a.name",
+				"path": [
+					{"kind": "field", "name": "Items", "field": "items"},
+					{"kind": "key", "key": "a"},
+					{"kind": "index", "index": 0}
+				],
+				"code": "too_short",
+				"params": {"min": 3},
+				"message": "must be at least 3 characters long",
+				"error": "oops"
+			}`,
+		},
+		{
+			name: "non-string key",
+			err: &Error{
+				Path: []PathSegment{{Kind: KeySegment, Key: 42}},
+			},
+			want: `{"path": [{"kind": "key", "key": "42"}]}`,
+		},
+		{
+			name: "warning",
+			err: &Error{
+				Field: "tag", Severity: SeverityWarning, Msg: "is deprecated",
+			},
+			want: `{
+				"field": "tag",
+				"severity": "warning",
+				"message": "is deprecated"
+			}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.err)
+			require.NoError(t, err)
+
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestError_MarshalJSON_value(t *testing.T) {
+	err := Error{Field: "name", Code: CodeRequired, Msg: "is required"}
+	want := `{"field": "name", "code": "required", "message": "is required"}`
+
+	got, jerr := json.Marshal(err)
+	require.NoError(t, jerr)
+	assert.JSONEq(t, want, string(got))
+
+	got, jerr = json.Marshal(struct {
+		Error
+	}{Error: err})
+	require.NoError(t, jerr)
+	assert.JSONEq(t, want, string(got))
+
+	got, jerr = json.Marshal([]Error{err})
+	require.NoError(t, jerr)
+	assert.JSONEq(t, "["+want+"]", string(got))
+
+	got, jerr = json.Marshal((*Error)(nil))
+	require.NoError(t, jerr)
+	assert.Equal(t, "null", string(got))
+}
+
+func TestError_MarshalJSON_invalidKind(t *testing.T) {
+	_, err := json.Marshal(&Error{Path: []PathSegment{{Kind: 9}}})
+
+	assert.Error(t, err)
+}
+
+func TestError_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    *Error
+		wantErr bool
+	}{
+		{
+			name: "all fields",
+			data: `{
+				"field": "items.a",
+				"path": [
+					{"kind": "field", "name": "Items", "field": "items"},
+					{"kind": "key", "key": "a"},
+					{"kind": "index", "index": 2}
+				],
+				"code": "too_small",
+				"params": {"min": 3},
+				"message": "must be at least 3",
+				"error": "oops"
+			}`,
+			want: &Error{
+				Field: "items.a",
+				Path: []PathSegment{
+					{Kind: FieldSegment, Name: "Items", Field: "items"},
+					{Kind: KeySegment, Key: "a"},
+					{Kind: IndexSegment, Index: 2},
+				},
+				Code:   CodeTooSmall,
+				Params: map[string]interface{}{"min": float64(3)},
+				Msg:    "must be at least 3",
+				Err:    errors.New("oops"),
+			},
+		},
+		{
+			name: "info",
+			data: `{"field": "tag", "severity": "info", "message": "is old"}`,
+			want: &Error{Field: "tag", Severity: SeverityInfo, Msg: "is old"},
+		},
+		{
+			name:    "invalid severity",
+			data:    `{"severity": "fatal"}`,
+			wantErr: true,
+		},
+		{
+			name: "cycle sentinel",
+			data: `{"field": "next", "error": "reference cycle detected"}`,
+			want: &Error{Field: "next", Err: ErrCycle},
+		},
+		{
+			name:    "invalid kind",
+			data:    `{"path": [{"kind": "bogus"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid JSON",
+			data:    `[]`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := &Error{}
+			err := json.Unmarshal([]byte(tt.data), got)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestError_UnmarshalJSON_sentinels(t *testing.T) {
+	for _, sentinel := range []error{ErrCycle, ErrNotAddressable} {
+		b, err := json.Marshal(&Error{Field: "foo", Err: sentinel})
+		require.NoError(t, err)
+
+		got := &Error{}
+		require.NoError(t, json.Unmarshal(b, got))
+
+		assert.True(t, errors.Is(got, sentinel))
+	}
+}
+
+func TestErrorList(t *testing.T) {
+	assert.Nil(t, NewErrorList(nil))
+	assert.NoError(t, ErrorList(nil).Err())
+
+	errs := Append(nil, &Error{
+		Field: "name",
+		Path:  []PathSegment{{Kind: FieldSegment, Name: "Name", Field: "name"}},
+		Code:  CodeRequired,
+		Msg:   "is required",
+	})
+	errs = Append(errs, errors.New("oops"))
+
+	list := NewErrorList(errs)
+
+	assert.Equal(t, ErrorList{
+		Errors(errs)[0].(*Error), //nolint:errorlint
+		{Err: errors.New("oops")},
+	}, list)
+
+	b, err := json.Marshal(list)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{
+			"field": "name",
+			"path": [{"kind": "field", "name": "Name", "field": "name"}],
+			"code": "required",
+			"message": "is required"
+		},
+		{"error": "oops"}
+	]`, string(b))
+
+	var got ErrorList
+	require.NoError(t, json.Unmarshal(b, &got))
+
+	assert.Equal(t, list, got)
+	assert.Equal(t, "name: is required; oops", got.Err().Error())
+}
@@ -36,7 +36,7 @@ func (s *Spec) Validate() error {
 		for i, c := range s.Containers {
 			if c.ImageRef != "" && !imgs[c.ImageRef] {
 				errs = validate.Append(errs, &validate.Error{
-					Field: fmt.Sprintf("containers.%d.imageRef", i),
+					Field: fmt.Sprintf("Containers.%d.ImageRef", i),
 					Msg: fmt.Sprintf(
 						"image with name '%s' not found", c.ImageRef,
 					),
@@ -79,6 +79,12 @@ func (s *Image) Validate() error {
 	errs = validate.Append(errs, validate.RequireField("URI", s.URI))
 	errs = validate.Append(errs, validate.RequireField("Tag", s.Tag))
 
+	if s.Tag == "latest" {
+		errs = validate.AppendFieldWarning(errs,
+			"Tag", "latest is deprecated, use a specific version",
+		)
+	}
+
 	return errs
 }
 
@@ -98,13 +104,22 @@ func main() {
 				{
 					Name: "server",
 				},
+				{
+					Name: "worker",
+					URI:  "registry.example.com/worker",
+					Tag:  "latest",
+				},
 			},
 		},
 	}
 
-	errs := validate.Validate(manifest)
+	res := validate.Check(manifest)
 
-	for _, err := range validate.Errors(errs) {
+	for _, err := range validate.Errors(res.Errors) {
 		fmt.Println(err)
 	}
+
+	for _, warn := range validate.Errors(res.Warnings) {
+		fmt.Println("warning:", warn)
+	}
 }
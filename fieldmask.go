@@ -0,0 +1,170 @@
+package validate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// FieldMask is a set of paths to values within a object, which limits
+// validation to those values. It is typically used to validate partial
+// updates, like PATCH requests, where fields omitted by the client must not be
+// reported as missing.
+//
+// Paths are made up of the same segments as rendered by the default
+// FieldJoinFunc, joined with dots: struct fields by their display name as
+// returned by the FieldNameFunc, slice and array items by index, and map
+// entries by key. A path covers the value it refers to, and all values nested
+// within it.
+type FieldMask struct {
+	all      bool
+	children map[string]*FieldMask
+}
+
+// NewFieldMask returns a FieldMask covering the given dot-separated paths, like
+// "name" or "address.city". A empty path covers the whole object.
+func NewFieldMask(paths ...string) *FieldMask {
+	m := &FieldMask{}
+	for _, p := range paths {
+		if p == "" {
+			m.add(nil)
+
+			continue
+		}
+
+		m.add(strings.Split(p, "."))
+	}
+
+	return m
+}
+
+// FieldMaskFromJSON returns a FieldMask covering all keys present in the given
+// JSON object, such as the body of a JSON Merge Patch request. Nested objects
+// are descended into, so only keys present within them are covered, while all
+// other values, including arrays and nulls, are covered as a whole.
+func FieldMaskFromJSON(data []byte) (*FieldMask, error) {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("validate: field mask JSON must be a object")
+	}
+
+	m := &FieldMask{}
+	m.addJSON(obj)
+
+	return m, nil
+}
+
+// add adds the given path to the mask.
+func (s *FieldMask) add(path []string) {
+	m := s
+	for _, seg := range path {
+		if m.all {
+			return
+		}
+
+		if m.children == nil {
+			m.children = map[string]*FieldMask{}
+		}
+
+		child, ok := m.children[seg]
+		if !ok {
+			child = &FieldMask{}
+			m.children[seg] = child
+		}
+		m = child
+	}
+
+	m.all = true
+	m.children = nil
+}
+
+// addJSON adds all keys of obj to the mask, descending into non-empty objects.
+func (s *FieldMask) addJSON(obj map[string]interface{}) {
+	for key, v := range obj {
+		if s.children == nil {
+			s.children = map[string]*FieldMask{}
+		}
+
+		child := &FieldMask{}
+		s.children[key] = child
+
+		if nested, ok := v.(map[string]interface{}); ok && len(nested) > 0 {
+			child.addJSON(nested)
+		} else {
+			child.all = true
+		}
+	}
+}
+
+// Covers reports if the given dot-separated path is covered by the mask, either
+// directly, or by one of its parent paths.
+func (s *FieldMask) Covers(path string) bool {
+	if path == "" {
+		return s.covers(nil)
+	}
+
+	return s.covers(strings.Split(path, "."))
+}
+
+// covers reports if the given path is covered by the mask.
+func (s *FieldMask) covers(path []string) bool {
+	m := s
+	for _, seg := range path {
+		if m.all {
+			return true
+		}
+
+		m = m.children[seg]
+		if m == nil {
+			return false
+		}
+	}
+
+	return m.all
+}
+
+// includes reports if the given path is covered by the mask, or is a parent of
+// a path covered by the mask, meaning values at the path must be walked.
+func (s *FieldMask) includes(path []string) bool {
+	m := s
+	for _, seg := range path {
+		if m.all {
+			return true
+		}
+
+		m = m.children[seg]
+		if m == nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+type fieldMaskContextKey struct{}
+
+// ContextWithFieldMask returns a copy of ctx with the given FieldMask. When ctx
+// is passed to ValidateContext, only values covered by the mask and their
+// parents are walked, and only errors for paths covered by the mask are
+// reported. ValidatableWithContext objects can also retrieve the mask with
+// FieldMaskFromContext.
+func ContextWithFieldMask(
+	ctx context.Context,
+	mask *FieldMask,
+) context.Context {
+	return context.WithValue(ctx, fieldMaskContextKey{}, mask)
+}
+
+// FieldMaskFromContext returns the FieldMask stored in ctx by
+// ContextWithFieldMask, or nil if none is set.
+func FieldMaskFromContext(ctx context.Context) *FieldMask {
+	mask, _ := ctx.Value(fieldMaskContextKey{}).(*FieldMask)
+
+	return mask
+}
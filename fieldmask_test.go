@@ -0,0 +1,197 @@
+package validate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldMask_Covers(t *testing.T) {
+	mask := NewFieldMask("name", "address.city", "items.1", "address.city.x")
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "", want: false},
+		{path: "name", want: true},
+		{path: "name.first", want: true},
+		{path: "address", want: false},
+		{path: "address.city", want: true},
+		{path: "address.zip", want: false},
+		{path: "items", want: false},
+		{path: "items.0", want: false},
+		{path: "items.1", want: true},
+		{path: "items.1.title", want: true},
+		{path: "email", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, mask.Covers(tt.path))
+		})
+	}
+
+	assert.True(t, NewFieldMask("").Covers(""))
+	assert.True(t, NewFieldMask("name", "").Covers("email"))
+	assert.False(t, NewFieldMask().Covers("name"))
+}
+
+func TestFieldMaskFromJSON(t *testing.T) {
+	mask, err := FieldMaskFromJSON([]byte(`{
+		"name": "alice",
+		"address": {"city": "Oslo", "geo": {"lat": 1}},
+		"tags": ["a"],
+		"manager": null,
+		"meta": {}
+	}`))
+	require.NoError(t, err)
+
+	for _, path := range []string{
+		"name", "address.city", "address.geo.lat", "tags", "tags.0",
+		"manager", "manager.name", "meta", "meta.foo",
+	} {
+		assert.True(t, mask.Covers(path), path)
+	}
+	for _, path := range []string{
+		"", "email", "address", "address.zip", "address.geo.lng",
+	} {
+		assert.False(t, mask.Covers(path), path)
+	}
+
+	_, err = FieldMaskFromJSON([]byte(`[1, 2]`))
+	assert.EqualError(t, err, "validate: field mask JSON must be a object")
+
+	_, err = FieldMaskFromJSON([]byte(`{`))
+	assert.Error(t, err)
+}
+
+type testMaskAddress struct {
+	City string `json:"city"`
+	Zip  string `json:"zip"`
+}
+
+func (s *testMaskAddress) Validate() error {
+	errs := RequireField("City", s.City)
+
+	return Append(errs, RequireField("Zip", s.Zip))
+}
+
+type testMaskUser struct {
+	Name    string             `json:"name" validate:"required"`
+	Email   string             `json:"email" validate:"required"`
+	Address *testMaskAddress   `json:"address"`
+	Items   []*testMaskAddress `json:"items"`
+
+	calls *int
+}
+
+func (s *testMaskUser) Validate() error {
+	if s.calls != nil {
+		*s.calls++
+	}
+
+	return AppendError(nil, "is invalid")
+}
+
+func TestValidator_ValidateContext_fieldMask(t *testing.T) {
+	required := func(field string) error {
+		return &Error{Field: field, Code: CodeRequired, Msg: "is required"}
+	}
+
+	tests := []struct {
+		name string
+		mask *FieldMask
+		want []error
+	}{
+		{
+			name: "no mask",
+			want: []error{
+				&Error{Msg: "is invalid"},
+				required("name"),
+				required("email"),
+				required("address.zip"),
+				required("items.0.city"),
+				required("items.1.zip"),
+			},
+		},
+		{
+			name: "empty mask",
+			mask: NewFieldMask(),
+			want: []error{},
+		},
+		{
+			name: "whole object",
+			mask: NewFieldMask(""),
+			want: []error{
+				&Error{Msg: "is invalid"},
+				required("name"),
+				required("email"),
+				required("address.zip"),
+				required("items.0.city"),
+				required("items.1.zip"),
+			},
+		},
+		{
+			name: "partial",
+			mask: NewFieldMask("email", "address.city", "items.1"),
+			want: []error{
+				required("email"),
+				required("items.1.zip"),
+			},
+		},
+		{
+			name: "nested object",
+			mask: NewFieldMask("address"),
+			want: []error{
+				required("address.zip"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := &testMaskUser{
+				Address: &testMaskAddress{City: "Oslo"},
+				Items: []*testMaskAddress{
+					{Zip: "0150"},
+					{City: "Bergen"},
+				},
+			}
+
+			ctx := context.Background()
+			if tt.mask != nil {
+				ctx = ContextWithFieldMask(ctx, tt.mask)
+			}
+
+			err := New(WithTagRules()).ValidateContext(ctx, obj)
+
+			assert.Equal(t, tt.want, withoutPaths(Errors(err)))
+		})
+	}
+}
+
+func TestValidator_ValidateContext_fieldMaskSkipsSubtrees(t *testing.T) {
+	calls := 0
+	obj := map[string]*testMaskUser{
+		"a": {calls: &calls},
+		"b": {calls: &calls},
+	}
+	ctx := ContextWithFieldMask(context.Background(), NewFieldMask("b.name"))
+
+	err := New(WithTagRules()).ValidateContext(ctx, obj)
+
+	assert.Equal(t, []error{
+		&Error{Field: "b.name", Code: CodeRequired, Msg: "is required"},
+	}, withoutPaths(Errors(err)))
+	assert.Equal(t, 1, calls)
+}
+
+func TestFieldMaskFromContext(t *testing.T) {
+	mask := NewFieldMask("name")
+
+	assert.Nil(t, FieldMaskFromContext(context.Background()))
+	assert.Same(t, mask, FieldMaskFromContext(
+		ContextWithFieldMask(context.Background(), mask),
+	))
+}
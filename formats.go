@@ -0,0 +1,269 @@
+package validate
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	uuidRegexp = regexp.MustCompile(
+		`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-` +
+			`[0-9a-fA-F]{12}$`,
+	)
+	hexColorRegexp = regexp.MustCompile(
+		`^#(?:[0-9a-fA-F]{3,4}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`,
+	)
+	semverRegexp = regexp.MustCompile(
+		`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+			`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)` +
+			`(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+			`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`,
+	)
+	e164Regexp = regexp.MustCompile(`^\+[1-9]\d{1,14}$`)
+)
+
+// iso8601Layouts are the ISO 8601 extended formats accepted by ISO8601.
+// Fractional seconds are accepted by time.Parse after the seconds of any
+// layout.
+var iso8601Layouts = []string{
+	"2006-01-02",
+	"2006-01-02T15:04",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04:05Z07:00",
+	"2006-01-02T15:04:05Z0700",
+}
+
+// Email returns a Error type for the given field if provided value is not a
+// plain email address as defined by the addr-spec of RFC 5322, without a
+// display name or angle brackets.
+func Email(field string, value string) error {
+	// Wrapping the value in angle brackets only allows a addr-spec, while
+	// still accepting quoted local parts, which are unquoted once parsed.
+	_, err := mail.ParseAddress("<" + value + ">")
+	if err != nil || strings.TrimSpace(value) != value ||
+		strings.ContainsAny(value, "<>") {
+		return formatError(field, "email", "must be a valid email address", nil)
+	}
+
+	return nil
+}
+
+// URL returns a Error type for the given field if provided value is not a
+// absolute URL or URI. If any schemes are given, the URL's scheme must match
+// one of them, ignoring case.
+func URL(field string, value string, schemes ...string) error {
+	msg := "must be a valid URL"
+	var params map[string]interface{}
+	if len(schemes) > 0 {
+		msg += " with scheme " + strings.Join(schemes, ", ")
+		params = map[string]interface{}{"schemes": schemes}
+	}
+
+	u, err := url.Parse(value)
+	if err != nil || u.Scheme == "" ||
+		(u.Host == "" && u.Opaque == "" && u.Path == "") {
+		return formatError(field, "url", msg, params)
+	}
+
+	if len(schemes) == 0 {
+		return nil
+	}
+
+	for _, scheme := range schemes {
+		if strings.EqualFold(u.Scheme, scheme) {
+			return nil
+		}
+	}
+
+	return formatError(field, "url", msg, params)
+}
+
+// Hostname returns a Error type for the given field if provided value is not a
+// valid hostname as defined by RFC 1123. A single trailing dot is allowed.
+func Hostname(field string, value string) error {
+	if !isHostname(value) {
+		return formatError(field, "hostname", "must be a valid hostname", nil)
+	}
+
+	return nil
+}
+
+// IPv4 returns a Error type for the given field if provided value is not a
+// IPv4 address in dotted decimal notation.
+func IPv4(field string, value string) error {
+	ip := net.ParseIP(value)
+	if ip == nil || ip.To4() == nil || strings.Contains(value, ":") {
+		return formatError(field, "ipv4", "must be a valid IPv4 address", nil)
+	}
+
+	return nil
+}
+
+// IPv6 returns a Error type for the given field if provided value is not a
+// IPv6 address.
+func IPv6(field string, value string) error {
+	if net.ParseIP(value) == nil || !strings.Contains(value, ":") {
+		return formatError(field, "ipv6", "must be a valid IPv6 address", nil)
+	}
+
+	return nil
+}
+
+// CIDR returns a Error type for the given field if provided value is not a
+// IPv4 or IPv6 address and prefix length in CIDR notation, like "192.0.2.0/24"
+// or "2001:db8::/32".
+func CIDR(field string, value string) error {
+	if _, _, err := net.ParseCIDR(value); err != nil {
+		return formatError(field, "cidr", "must be a valid CIDR notation", nil)
+	}
+
+	return nil
+}
+
+// UUID returns a Error type for the given field if provided value is not a UUID
+// in its canonical hyphenated form. If version is not zero, the UUID must also
+// be of the given version, and of the RFC 4122 variant.
+func UUID(field string, value string, version int) error {
+	msg := "must be a valid UUID"
+	var params map[string]interface{}
+	if version != 0 {
+		msg = fmt.Sprintf("must be a valid version %d UUID", version)
+		params = map[string]interface{}{"version": version}
+	}
+
+	if !uuidRegexp.MatchString(value) {
+		return formatError(field, "uuid", msg, params)
+	}
+
+	if version == 0 {
+		return nil
+	}
+
+	v, _ := strconv.ParseInt(value[14:15], 16, 0)
+	if int(v) != version || !strings.ContainsAny(value[19:20], "89abAB") {
+		return formatError(field, "uuid", msg, params)
+	}
+
+	return nil
+}
+
+// RFC3339 returns a Error type for the given field if provided value is not a
+// timestamp as defined by RFC 3339, like "2006-01-02T15:04:05Z" or
+// "2006-01-02T15:04:05.999+07:00".
+func RFC3339(field string, value string) error {
+	if _, err := time.Parse(time.RFC3339, value); err != nil {
+		return formatError(
+			field, "rfc3339", "must be a valid RFC 3339 timestamp", nil,
+		)
+	}
+
+	return nil
+}
+
+// ISO8601 returns a Error type for the given field if provided value is not a
+// date or timestamp in ISO 8601 extended format. Besides RFC 3339 timestamps,
+// plain dates, timestamps without seconds, and timestamps without a time zone
+// are accepted.
+func ISO8601(field string, value string) error {
+	for _, layout := range iso8601Layouts {
+		if _, err := time.Parse(layout, value); err == nil {
+			return nil
+		}
+	}
+
+	return formatError(
+		field, "iso8601", "must be a valid ISO 8601 timestamp", nil,
+	)
+}
+
+// HexColor returns a Error type for the given field if provided value is not a
+// hexadecimal color code with 3, 4, 6, or 8 digits prefixed with "#".
+func HexColor(field string, value string) error {
+	if !hexColorRegexp.MatchString(value) {
+		return formatError(field, "hexcolor", "must be a valid hex color", nil)
+	}
+
+	return nil
+}
+
+// Base64 returns a Error type for the given field if provided value is not
+// padded standard base64 encoded data as defined by RFC 4648.
+func Base64(field string, value string) error {
+	if _, err := base64.StdEncoding.DecodeString(value); err != nil ||
+		value == "" {
+		return formatError(field, "base64", "must be valid base64", nil)
+	}
+
+	return nil
+}
+
+// Semver returns a Error type for the given field if provided value is not a
+// version as defined by Semantic Versioning 2.0.0, like "1.2.3-rc.1+build.5".
+// A "v" prefix is not allowed.
+func Semver(field string, value string) error {
+	if !semverRegexp.MatchString(value) {
+		return formatError(
+			field, "semver", "must be a valid semantic version", nil,
+		)
+	}
+
+	return nil
+}
+
+// E164 returns a Error type for the given field if provided value is not a
+// phone number in E.164 format, like "+14155552671".
+func E164(field string, value string) error {
+	if !e164Regexp.MatchString(value) {
+		return formatError(
+			field, "e164", "must be a valid E.164 phone number", nil,
+		)
+	}
+
+	return nil
+}
+
+// formatError returns a error for a value not in the named format. The format
+// name is added to params, which may be nil.
+func formatError(
+	field, format, msg string,
+	params map[string]interface{},
+) *Error {
+	p := map[string]interface{}{"format": format}
+	for k, v := range params {
+		p[k] = v
+	}
+
+	return &Error{Field: field, Code: CodeInvalidFormat, Params: p, Msg: msg}
+}
+
+// isHostname checks if s is a valid hostname as defined by RFC 1123.
+func isHostname(s string) bool {
+	s = strings.TrimSuffix(s, ".")
+	if s == "" || len(s) > 253 {
+		return false
+	}
+
+	for _, label := range strings.Split(s, ".") {
+		if label == "" || len(label) > 63 ||
+			label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' ||
+				c >= '0' && c <= '9' || c == '-') {
+				return false
+			}
+		}
+	}
+
+	return true
+}
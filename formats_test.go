@@ -0,0 +1,243 @@
+package validate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormats(t *testing.T) {
+	type formatFunc func(field string, value string) error
+
+	uuidV4 := func(field string, value string) error {
+		return UUID(field, value, 4)
+	}
+	anyURL := func(field string, value string) error {
+		return URL(field, value)
+	}
+	httpURL := func(field string, value string) error {
+		return URL(field, value, "http", "https")
+	}
+
+	tests := []struct {
+		name    string
+		fn      formatFunc
+		msg     string
+		params  map[string]interface{}
+		valid   []string
+		invalid []string
+	}{
+		{
+			name:   "Email",
+			fn:     Email,
+			params: map[string]interface{}{"format": "email"},
+			msg:    "must be a valid email address",
+			valid: []string{
+				"jane@example.com",
+				"jane.doe+tag@sub.example.co.uk",
+				`"jane doe"@example.com`,
+			},
+			invalid: []string{
+				"",
+				"jane",
+				"jane@",
+				"Jane <jane@example.com>",
+				"<jane@example.com>",
+				"jane@example.com ",
+			},
+		},
+		{
+			name:   "URL",
+			fn:     anyURL,
+			params: map[string]interface{}{"format": "url"},
+			msg:    "must be a valid URL",
+			valid: []string{
+				"https://example.com",
+				"ftp://example.com/file.txt",
+				"mailto:jane@example.com",
+				"urn:isbn:0451450523",
+			},
+			invalid: []string{"", "example.com", "/path", "http://%zz"},
+		},
+		{
+			name: "URL with schemes",
+			fn:   httpURL,
+			params: map[string]interface{}{
+				"format":  "url",
+				"schemes": []string{"http", "https"},
+			},
+			msg:   "must be a valid URL with scheme http, https",
+			valid: []string{"http://example.com", "HTTPS://example.com/a?b=c"},
+			invalid: []string{
+				"ftp://example.com",
+				"mailto:jane@example.com",
+			},
+		},
+		{
+			name:   "Hostname",
+			fn:     Hostname,
+			params: map[string]interface{}{"format": "hostname"},
+			msg:    "must be a valid hostname",
+			valid: []string{
+				"localhost",
+				"example.com",
+				"example.com.",
+				"3com.com",
+				"a-b.example",
+			},
+			invalid: []string{
+				"",
+				"-example.com",
+				"example-.com",
+				"exa_mple.com",
+				"example..com",
+				strings.Repeat("a", 64) + ".com",
+			},
+		},
+		{
+			name:    "IPv4",
+			fn:      IPv4,
+			params:  map[string]interface{}{"format": "ipv4"},
+			msg:     "must be a valid IPv4 address",
+			valid:   []string{"192.0.2.1", "0.0.0.0", "255.255.255.255"},
+			invalid: []string{"", "256.0.0.1", "192.0.2", "::ffff:192.0.2.1"},
+		},
+		{
+			name:    "IPv6",
+			fn:      IPv6,
+			params:  map[string]interface{}{"format": "ipv6"},
+			msg:     "must be a valid IPv6 address",
+			valid:   []string{"::1", "2001:db8::1", "::ffff:192.0.2.1"},
+			invalid: []string{"", "192.0.2.1", "2001:db8::g", ":::1"},
+		},
+		{
+			name:    "CIDR",
+			fn:      CIDR,
+			params:  map[string]interface{}{"format": "cidr"},
+			msg:     "must be a valid CIDR notation",
+			valid:   []string{"192.0.2.0/24", "2001:db8::/32"},
+			invalid: []string{"", "192.0.2.0", "192.0.2.0/33"},
+		},
+		{
+			name: "UUID",
+			fn: func(field string, value string) error {
+				return UUID(field, value, 0)
+			},
+			params: map[string]interface{}{"format": "uuid"},
+			msg:    "must be a valid UUID",
+			valid: []string{
+				"123e4567-e89b-12d3-a456-426614174000",
+				"00000000-0000-0000-0000-000000000000",
+				"123E4567-E89B-12D3-A456-426614174000",
+			},
+			invalid: []string{
+				"",
+				"123e4567e89b12d3a456426614174000",
+				"123e4567-e89b-12d3-a456-42661417400g",
+				"{123e4567-e89b-12d3-a456-426614174000}",
+			},
+		},
+		{
+			name: "UUID version",
+			fn:   uuidV4,
+			params: map[string]interface{}{
+				"format": "uuid", "version": 4,
+			},
+			msg:   "must be a valid version 4 UUID",
+			valid: []string{"f47ac10b-58cc-4372-a567-0e02b2c3d479"},
+			invalid: []string{
+				"123e4567-e89b-12d3-a456-426614174000",
+				"f47ac10b-58cc-4372-c567-0e02b2c3d479",
+			},
+		},
+		{
+			name:   "RFC3339",
+			fn:     RFC3339,
+			params: map[string]interface{}{"format": "rfc3339"},
+			msg:    "must be a valid RFC 3339 timestamp",
+			valid: []string{
+				"2006-01-02T15:04:05Z",
+				"2006-01-02T15:04:05.999+07:00",
+			},
+			invalid: []string{
+				"",
+				"2006-01-02",
+				"2006-01-02T15:04:05",
+				"2006-13-02T15:04:05Z",
+			},
+		},
+		{
+			name:   "ISO8601",
+			fn:     ISO8601,
+			params: map[string]interface{}{"format": "iso8601"},
+			msg:    "must be a valid ISO 8601 timestamp",
+			valid: []string{
+				"2006-01-02",
+				"2006-01-02T15:04",
+				"2006-01-02T15:04:05",
+				"2006-01-02T15:04:05.123",
+				"2006-01-02T15:04:05Z",
+				"2006-01-02T15:04:05+0700",
+			},
+			invalid: []string{"", "2006-01", "02/01/2006", "2006-01-02 15:04"},
+		},
+		{
+			name:    "HexColor",
+			fn:      HexColor,
+			params:  map[string]interface{}{"format": "hexcolor"},
+			msg:     "must be a valid hex color",
+			valid:   []string{"#fff", "#ffff", "#FF0000", "#ff000080"},
+			invalid: []string{"", "fff", "#ff", "#fffff", "#ggg"},
+		},
+		{
+			name:    "Base64",
+			fn:      Base64,
+			params:  map[string]interface{}{"format": "base64"},
+			msg:     "must be valid base64",
+			valid:   []string{"Zm9v", "Zm9vYg==", "+/+/"},
+			invalid: []string{"", "Zm9vYg", "Zm9v!", "-_-_"},
+		},
+		{
+			name:   "Semver",
+			fn:     Semver,
+			params: map[string]interface{}{"format": "semver"},
+			msg:    "must be a valid semantic version",
+			valid: []string{
+				"0.0.0",
+				"1.2.3",
+				"1.2.3-rc.1",
+				"1.2.3-alpha-1+build.5",
+			},
+			invalid: []string{"", "1.2", "v1.2.3", "01.2.3", "1.2.3-01"},
+		},
+		{
+			name:    "E164",
+			fn:      E164,
+			params:  map[string]interface{}{"format": "e164"},
+			msg:     "must be a valid E.164 phone number",
+			valid:   []string{"+14155552671", "+442071838750"},
+			invalid: []string{"", "14155552671", "+0123", "+1415555267123456"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, value := range tt.valid {
+				assert.NoError(t, tt.fn("Field", value), value)
+			}
+
+			for _, value := range tt.invalid {
+				assert.Equal(t,
+					&Error{
+						Field:  "Field",
+						Code:   CodeInvalidFormat,
+						Params: tt.params,
+						Msg:    tt.msg,
+					},
+					tt.fn("Field", value),
+					value,
+				)
+			}
+		})
+	}
+}
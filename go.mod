@@ -6,4 +6,5 @@ require (
 	github.com/stretchr/testify v1.7.0
 	go.uber.org/atomic v1.9.0 // indirect
 	go.uber.org/multierr v1.7.0
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -1,13 +1,21 @@
 package validate
 
 import (
+	"errors"
+	"fmt"
 	"reflect"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/multierr"
 )
 
 // RequireField returns a Error type for the given field if provided value is
 // empty/zero.
 func RequireField(field string, value interface{}) error {
-	err := &Error{Field: field, Msg: "is required"}
+	err := &Error{Field: field, Code: CodeRequired, Msg: "is required"}
 	v := reflect.ValueOf(value)
 
 	if v.Kind() == reflect.Ptr {
@@ -34,3 +42,860 @@ func RequireField(field string, value interface{}) error {
 
 	return nil
 }
+
+// ForbidField returns a Error type for the given field if provided value is
+// not empty/zero. It is the inverse of RequireField.
+func ForbidField(field string, value interface{}) error {
+	if v, _ := indirect(value); isEmpty(v) {
+		return nil
+	}
+
+	return &Error{Field: field, Code: CodeNotEmpty, Msg: "must be empty"}
+}
+
+// RequireFieldIf returns a Error type for the given field if cond is true and
+// provided value is empty/zero, like when another field has a given value:
+//
+//  validate.RequireFieldIf("Reason", s.Reason, s.Status == "rejected")
+func RequireFieldIf(field string, value interface{}, cond bool) error {
+	if !cond {
+		return nil
+	}
+
+	return RequireField(field, value)
+}
+
+// RequireFieldWith returns a Error type for the given field if provided value
+// is empty/zero, while any of the other values are not empty/zero.
+func RequireFieldWith(
+	field string,
+	value interface{},
+	others ...interface{},
+) error {
+	return RequireFieldIf(field, value, countPresent(others) > 0)
+}
+
+// RequireFieldWithAll returns a Error type for the given field if provided
+// value is empty/zero, while all of the other values are not empty/zero.
+func RequireFieldWithAll(
+	field string,
+	value interface{},
+	others ...interface{},
+) error {
+	return RequireFieldIf(field, value,
+		len(others) > 0 && countPresent(others) == len(others),
+	)
+}
+
+// RequireFieldWithout returns a Error type for the given field if provided
+// value is empty/zero, while any of the other values are empty/zero.
+func RequireFieldWithout(
+	field string,
+	value interface{},
+	others ...interface{},
+) error {
+	return RequireFieldIf(field, value, countPresent(others) < len(others))
+}
+
+// RequireFieldWithoutAll returns a Error type for the given field if provided
+// value is empty/zero, while all of the other values are empty/zero. This
+// requires at least one of the values to be given:
+//
+//  validate.RequireFieldWithoutAll("Email", s.Email, s.Phone)
+func RequireFieldWithoutAll(
+	field string,
+	value interface{},
+	others ...interface{},
+) error {
+	return RequireFieldIf(field, value,
+		len(others) > 0 && countPresent(others) == 0,
+	)
+}
+
+// ForbidFieldIf returns a Error type for the given field if cond is true and
+// provided value is not empty/zero.
+func ForbidFieldIf(field string, value interface{}, cond bool) error {
+	if !cond {
+		return nil
+	}
+
+	return ForbidField(field, value)
+}
+
+// ForbidFieldWith returns a Error type for the given field if provided value
+// is not empty/zero, while any of the other values are not empty/zero. This
+// makes the values mutually exclusive:
+//
+//  validate.ForbidFieldWith("Token", s.Token, s.Password)
+func ForbidFieldWith(
+	field string,
+	value interface{},
+	others ...interface{},
+) error {
+	return ForbidFieldIf(field, value, countPresent(others) > 0)
+}
+
+// MinLength returns a Error type for the given field if provided value is
+// shorter than minimum. Strings are measured in runes, and arrays, slices, and
+// maps in number of items. Nil pointers have a length of zero. It panics if
+// value is not a string, array, slice, or map, or a pointer to one.
+func MinLength(field string, value interface{}, minimum int) error {
+	v, _ := indirect(value)
+	if n := mustLength("MinLength", v); n < minimum {
+		qty := fmt.Sprintf("at least %d", minimum)
+
+		params := map[string]interface{}{"min": minimum}
+
+		return lengthError(field, v, true, qty, minimum, params)
+	}
+
+	return nil
+}
+
+// MaxLength returns a Error type for the given field if provided value is
+// longer than maximum. Strings are measured in runes, and arrays, slices, and
+// maps in number of items. Nil pointers have a length of zero. It panics if
+// value is not a string, array, slice, or map, or a pointer to one.
+func MaxLength(field string, value interface{}, maximum int) error {
+	v, _ := indirect(value)
+	if n := mustLength("MaxLength", v); n > maximum {
+		qty := fmt.Sprintf("at most %d", maximum)
+
+		params := map[string]interface{}{"max": maximum}
+
+		return lengthError(field, v, false, qty, maximum, params)
+	}
+
+	return nil
+}
+
+// LengthBetween returns a Error type for the given field if the length of
+// provided value is not within minimum and maximum, inclusive. Strings are
+// measured in runes, and arrays, slices, and maps in number of items. Nil
+// pointers have a length of zero. It panics if value is not a string, array,
+// slice, or map, or a pointer to one.
+func LengthBetween(
+	field string,
+	value interface{},
+	minimum, maximum int,
+) error {
+	v, _ := indirect(value)
+	n := mustLength("LengthBetween", v)
+	if n >= minimum && n <= maximum {
+		return nil
+	}
+
+	qty := fmt.Sprintf("between %d and %d", minimum, maximum)
+	if minimum == maximum {
+		qty = fmt.Sprintf("exactly %d", minimum)
+	}
+
+	params := map[string]interface{}{"min": minimum, "max": maximum}
+
+	return lengthError(field, v, n < minimum, qty, maximum, params)
+}
+
+// Min returns a Error type for the given field if provided numeric value is
+// less than minimum. Nil pointers are ignored. It panics if value is not a
+// integer or float, or a pointer to one.
+func Min(field string, value interface{}, minimum float64) error {
+	n, ok := mustNumber("Min", value)
+	if ok && n < minimum {
+		return &Error{
+			Field:  field,
+			Code:   CodeTooSmall,
+			Params: map[string]interface{}{"min": minimum},
+			Msg:    fmt.Sprintf("must be at least %v", minimum),
+		}
+	}
+
+	return nil
+}
+
+// Max returns a Error type for the given field if provided numeric value is
+// greater than maximum. Nil pointers are ignored. It panics if value is not a
+// integer or float, or a pointer to one.
+func Max(field string, value interface{}, maximum float64) error {
+	n, ok := mustNumber("Max", value)
+	if ok && n > maximum {
+		return &Error{
+			Field:  field,
+			Code:   CodeTooLarge,
+			Params: map[string]interface{}{"max": maximum},
+			Msg:    fmt.Sprintf("must be at most %v", maximum),
+		}
+	}
+
+	return nil
+}
+
+// Between returns a Error type for the given field if provided numeric value is
+// not within minimum and maximum, inclusive. Nil pointers are ignored. It
+// panics if value is not a integer or float, or a pointer to one.
+func Between(field string, value interface{}, minimum, maximum float64) error {
+	n, ok := mustNumber("Between", value)
+	if ok && (n < minimum || n > maximum) {
+		code := CodeTooLarge
+		if n < minimum {
+			code = CodeTooSmall
+		}
+
+		return &Error{
+			Field:  field,
+			Code:   code,
+			Params: map[string]interface{}{"min": minimum, "max": maximum},
+			Msg:    fmt.Sprintf("must be between %v and %v", minimum, maximum),
+		}
+	}
+
+	return nil
+}
+
+// OneOf returns a Error type for the given field if provided value is not
+// equal to any of the given options. Options of the same kind as value are
+// converted to the type of value before comparison, allowing named string and
+// numeric types to be compared against untyped constants.
+func OneOf(field string, value interface{}, options ...interface{}) error {
+	v, _ := indirect(value)
+	for _, opt := range options {
+		if equalValues(v, reflect.ValueOf(opt)) {
+			return nil
+		}
+	}
+
+	return oneOfError(field, options)
+}
+
+// MatchRegexp returns a Error type for the given field if provided value does
+// not match the regular expression re.
+func MatchRegexp(field string, value string, re *regexp.Regexp) error {
+	if !re.MatchString(value) {
+		return &Error{
+			Field:  field,
+			Code:   CodePatternMismatch,
+			Params: map[string]interface{}{"pattern": re.String()},
+			Msg:    "must match " + re.String(),
+		}
+	}
+
+	return nil
+}
+
+// HasPrefix returns a Error type for the given field if provided value does
+// not start with prefix.
+func HasPrefix(field string, value string, prefix string) error {
+	if !strings.HasPrefix(value, prefix) {
+		return &Error{
+			Field:  field,
+			Code:   CodeMissingPrefix,
+			Params: map[string]interface{}{"prefix": prefix},
+			Msg:    fmt.Sprintf("must start with %q", prefix),
+		}
+	}
+
+	return nil
+}
+
+// HasSuffix returns a Error type for the given field if provided value does
+// not end with suffix.
+func HasSuffix(field string, value string, suffix string) error {
+	if !strings.HasSuffix(value, suffix) {
+		return &Error{
+			Field:  field,
+			Code:   CodeMissingSuffix,
+			Params: map[string]interface{}{"suffix": suffix},
+			Msg:    fmt.Sprintf("must end with %q", suffix),
+		}
+	}
+
+	return nil
+}
+
+// UniqueItems returns a Error type for the given field if provided slice or
+// array value contains duplicate items. Nil pointers are ignored. It panics if
+// value is not a slice or array, or a pointer to one.
+func UniqueItems(field string, value interface{}) error {
+	v, ok := indirect(value)
+	if !ok {
+		return nil
+	}
+
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		panic(fmt.Sprintf(
+			"validate: UniqueItems: unsupported type %s", v.Type(),
+		))
+	}
+
+	err := &Error{
+		Field: field,
+		Code:  CodeDuplicateItems,
+		Msg:   "must only contain unique items",
+	}
+	if hashable(v.Type().Elem()) {
+		seen := make(map[interface{}]bool, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			item := v.Index(i).Interface()
+			if seen[item] {
+				return err
+			}
+			seen[item] = true
+		}
+
+		return nil
+	}
+
+	for i := 0; i < v.Len(); i++ {
+		for j := i + 1; j < v.Len(); j++ {
+			a, b := v.Index(i).Interface(), v.Index(j).Interface()
+			if reflect.DeepEqual(a, b) {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// hashable checks if values of the given type can be used as map keys without
+// panicking. Interface types, and arrays and structs containing them, are
+// comparable but panic if their dynamic values are not.
+func hashable(t reflect.Type) bool {
+	switch t.Kind() { //nolint:exhaustive
+	case reflect.Interface:
+		return false
+	case reflect.Array:
+		return hashable(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if !hashable(t.Field(i).Type) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return t.Comparable()
+}
+
+// NonNegativeDuration returns a Error type for the given field if provided
+// duration is negative.
+func NonNegativeDuration(field string, value time.Duration) error {
+	if value < 0 {
+		return &Error{
+			Field: field, Code: CodeNegative, Msg: "must not be negative",
+		}
+	}
+
+	return nil
+}
+
+// EqualField returns a Error type for the given field if provided value is not
+// equal to the value of the other field, like a password confirmation:
+//
+//  validate.EqualField(
+//      "PasswordConfirm", s.PasswordConfirm, "Password", s.Password,
+//  )
+//
+// The error's "other" param is a FieldRef to the other field, which is
+// replaced with its display name, like "password", when returned from a
+// Validate method. Nil pointers are compared as the zero value of their type,
+// and time.Time values are compared with their Equal method.
+func EqualField(
+	field string,
+	value interface{},
+	other string,
+	otherValue interface{},
+) error {
+	if sameValues(value, otherValue) {
+		return nil
+	}
+
+	return fieldRefError(field, CodeNotEqualField,
+		"must be equal to "+other, other, false,
+	)
+}
+
+// NotEqualField returns a Error type for the given field if provided value is
+// equal to the value of the other field. It is the inverse of EqualField.
+func NotEqualField(
+	field string,
+	value interface{},
+	other string,
+	otherValue interface{},
+) error {
+	if !sameValues(value, otherValue) {
+		return nil
+	}
+
+	return fieldRefError(field, CodeEqualField,
+		"must not be equal to "+other, other, false,
+	)
+}
+
+// LessField returns a Error type for the given field if provided value is not
+// less than the value of the other field, like a start time which must be
+// before the end time:
+//
+//  validate.LessField("StartTime", s.StartTime, "EndTime", s.EndTime)
+//
+// Numbers, including time.Duration values, and time.Time values are supported,
+// and it panics for other types. The comparison is skipped if either value is
+// a nil pointer. Like EqualField, the error's "other" param is a FieldRef.
+func LessField(
+	field string,
+	value interface{},
+	other string,
+	otherValue interface{},
+) error {
+	return orderLess.check("LessField", field, value, other, otherValue)
+}
+
+// LessOrEqualField returns a Error type for the given field if provided value
+// is greater than the value of the other field. See LessField for details.
+func LessOrEqualField(
+	field string,
+	value interface{},
+	other string,
+	otherValue interface{},
+) error {
+	return orderLessOrEqual.check(
+		"LessOrEqualField", field, value, other, otherValue,
+	)
+}
+
+// GreaterField returns a Error type for the given field if provided value is
+// not greater than the value of the other field. See LessField for details.
+func GreaterField(
+	field string,
+	value interface{},
+	other string,
+	otherValue interface{},
+) error {
+	return orderGreater.check("GreaterField", field, value, other, otherValue)
+}
+
+// GreaterOrEqualField returns a Error type for the given field if provided
+// value is less than the value of the other field. See LessField for details.
+func GreaterOrEqualField(
+	field string,
+	value interface{},
+	other string,
+	otherValue interface{},
+) error {
+	return orderGreaterOrEqual.check(
+		"GreaterOrEqualField", field, value, other, otherValue,
+	)
+}
+
+// WithMessage overrides the Msg of all *Error values within err, allowing the
+// default messages of helpers to be customized. The Code and Params of errors
+// are left as is. The err value is returned as is, so it can be used to
+// directly wrap helpers:
+//
+//  errs = validate.Append(errs, validate.WithMessage(
+//      validate.MinLength("Name", s.Name, 3), "is too short",
+//  ))
+func WithMessage(err error, msg string) error {
+	for _, e := range multierr.Errors(err) {
+		var vErr *Error
+		if errors.As(e, &vErr) {
+			vErr.Msg = msg
+		}
+	}
+
+	return err
+}
+
+// WithSeverity sets the Severity of all *Error values within err, allowing
+// helpers to report warnings instead of errors. Like WithMessage, the err value
+// is returned as is:
+//
+//  errs = validate.Append(errs, validate.WithSeverity(
+//      validate.RequireField("Tag", s.Tag), validate.SeverityWarning,
+//  ))
+func WithSeverity(err error, severity Severity) error {
+	for _, e := range multierr.Errors(err) {
+		var vErr *Error
+		if errors.As(e, &vErr) {
+			vErr.Severity = severity
+		}
+	}
+
+	return err
+}
+
+// indirect returns the value of the given value, dereferencing pointers. For
+// nil pointers the zero value of the pointed to type is returned along with
+// false, and for nil values a invalid reflect.Value.
+func indirect(value interface{}) (reflect.Value, bool) {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			t := v.Type().Elem()
+			for t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+
+			return reflect.Zero(t), false
+		}
+		v = v.Elem()
+	}
+
+	return v, v.IsValid()
+}
+
+// countPresent returns the number of values which are not empty/zero.
+func countPresent(values []interface{}) int {
+	n := 0
+	for _, value := range values {
+		if v, _ := indirect(value); !isEmpty(v) {
+			n++
+		}
+	}
+
+	return n
+}
+
+// isEmpty checks if the given value is a nil pointer, a empty map or slice,
+// or a zero value.
+func isEmpty(v reflect.Value) bool {
+	if !v.IsValid() {
+		return true
+	}
+
+	switch v.Kind() { //nolint:exhaustive
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	case reflect.Map, reflect.Slice:
+		return v.Len() == 0
+	}
+
+	return v.IsZero()
+}
+
+// length returns the length of strings in runes, and of arrays, slices, and
+// maps in items. The second return value is false for all other kinds.
+func length(v reflect.Value) (int, bool) {
+	switch v.Kind() { //nolint:exhaustive
+	case reflect.String:
+		return utf8.RuneCountInString(v.String()), true
+	case reflect.Array, reflect.Slice, reflect.Map:
+		return v.Len(), true
+	}
+
+	return 0, false
+}
+
+// mustLength returns the length of v like length, treating invalid values as
+// having a length of zero, and panics for unsupported kinds.
+func mustLength(helper string, v reflect.Value) int {
+	if !v.IsValid() {
+		return 0
+	}
+
+	n, ok := length(v)
+	if !ok {
+		panic(fmt.Sprintf(
+			"validate: %s: unsupported type %s", helper, v.Type(),
+		))
+	}
+
+	return n
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// number returns the value of integers, unsigned integers, and floats as a
+// float64. The second return value is false for all other kinds.
+func number(v reflect.Value) (float64, bool) {
+	switch v.Kind() { //nolint:exhaustive
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+
+	return 0, false
+}
+
+// mustNumber returns the numeric value of value like number, after
+// dereferencing pointers. The second return value is false for nil values, and
+// it panics for unsupported kinds.
+func mustNumber(helper string, value interface{}) (float64, bool) {
+	v, ok := indirect(value)
+	if !ok {
+		return 0, false
+	}
+
+	n, ok := number(v)
+	if !ok {
+		panic(fmt.Sprintf(
+			"validate: %s: unsupported type %s", helper, v.Type(),
+		))
+	}
+
+	return n, true
+}
+
+// equalValues checks if a and b are equal. If b is of the same kind as a, or
+// both are signed integers, unsigned integers, or floats, b is converted to the
+// type of a before comparing.
+func equalValues(a, b reflect.Value) bool {
+	if !a.IsValid() || !b.IsValid() {
+		return a.IsValid() == b.IsValid()
+	}
+
+	if kindClass(a.Kind()) == kindClass(b.Kind()) &&
+		b.Type().ConvertibleTo(a.Type()) {
+		b = b.Convert(a.Type())
+	}
+
+	if a.Type() != b.Type() {
+		return false
+	}
+
+	if a.Type().Comparable() {
+		return a.Interface() == b.Interface()
+	}
+
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
+
+// kindClass groups signed integer, unsigned integer, and float kinds, and
+// returns all other kinds as is.
+func kindClass(k reflect.Kind) reflect.Kind {
+	switch k { //nolint:exhaustive
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		return reflect.Int
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		return reflect.Uint
+	case reflect.Float32, reflect.Float64:
+		return reflect.Float64
+	}
+
+	return k
+}
+
+// sameValues checks if a and b are equal after dereferencing pointers, with
+// time.Time values compared with their Equal method.
+func sameValues(a, b interface{}) bool {
+	x, _ := indirect(a)
+	y, _ := indirect(b)
+
+	if c, ok := compareValues(x, y); ok {
+		return c == 0
+	}
+
+	return equalValues(x, y)
+}
+
+// compareValues compares a and b, returning -1, 0, or 1 if a is less than,
+// equal to, or greater than b. Both must be numbers, or both time.Time values,
+// otherwise the second return value is false.
+func compareValues(a, b reflect.Value) (int, bool) {
+	if !a.IsValid() || !b.IsValid() {
+		return 0, false
+	}
+
+	if a.Type() == timeType || b.Type() == timeType {
+		if a.Type() != b.Type() {
+			return 0, false
+		}
+
+		x := a.Interface().(time.Time) //nolint:forcetypeassert
+		y := b.Interface().(time.Time) //nolint:forcetypeassert
+		switch {
+		case x.Before(y):
+			return -1, true
+		case x.After(y):
+			return 1, true
+		}
+
+		return 0, true
+	}
+
+	// Integers are compared exactly, as converting them to float64 loses
+	// precision beyond 2^53.
+	switch ka, kb := kindClass(a.Kind()), kindClass(b.Kind()); {
+	case ka == reflect.Int && kb == reflect.Int:
+		return compareInts(a.Int(), b.Int()), true
+	case ka == reflect.Uint && kb == reflect.Uint:
+		return compareUints(a.Uint(), b.Uint()), true
+	case ka == reflect.Int && kb == reflect.Uint:
+		return compareIntUint(a.Int(), b.Uint()), true
+	case ka == reflect.Uint && kb == reflect.Int:
+		return -compareIntUint(b.Int(), a.Uint()), true
+	}
+
+	x, ok := number(a)
+	if !ok {
+		return 0, false
+	}
+	y, ok := number(b)
+	if !ok {
+		return 0, false
+	}
+
+	return compareFloats(x, y), true
+}
+
+// compareIntUint compares a signed with a unsigned integer, like compareInts.
+func compareIntUint(x int64, y uint64) int {
+	if x < 0 {
+		return -1
+	}
+
+	return compareUints(uint64(x), y)
+}
+
+// fieldOrder describes a ordering constraint between two fields.
+type fieldOrder struct {
+	code    string
+	msg     string
+	timeMsg string
+	valid   func(c int) bool
+}
+
+var (
+	orderLess = fieldOrder{
+		code:    CodeNotLessField,
+		msg:     "must be less than ",
+		timeMsg: "must be before ",
+		valid:   func(c int) bool { return c < 0 },
+	}
+	orderLessOrEqual = fieldOrder{
+		code:    CodeNotLessOrEqualField,
+		msg:     "must be less than or equal to ",
+		timeMsg: "must not be after ",
+		valid:   func(c int) bool { return c <= 0 },
+	}
+	orderGreater = fieldOrder{
+		code:    CodeNotGreaterField,
+		msg:     "must be greater than ",
+		timeMsg: "must be after ",
+		valid:   func(c int) bool { return c > 0 },
+	}
+	orderGreaterOrEqual = fieldOrder{
+		code:    CodeNotGreaterOrEqualField,
+		msg:     "must be greater than or equal to ",
+		timeMsg: "must not be before ",
+		valid:   func(c int) bool { return c >= 0 },
+	}
+)
+
+// check compares value to otherValue, and returns a error for field if the
+// result is not valid for the ordering. It panics if the values cannot be
+// compared.
+func (s fieldOrder) check(
+	helper string,
+	field string,
+	value interface{},
+	other string,
+	otherValue interface{},
+) error {
+	a, aok := indirect(value)
+	b, bok := indirect(otherValue)
+	if !aok || !bok {
+		return nil
+	}
+
+	c, ok := compareValues(a, b)
+	if !ok {
+		panic(fmt.Sprintf(
+			"validate: %s: cannot compare %s with %s",
+			helper, a.Type(), b.Type(),
+		))
+	}
+
+	if s.valid(c) {
+		return nil
+	}
+
+	if a.Type() == timeType {
+		return fieldRefError(field, s.code, s.timeMsg+other, other, true)
+	}
+
+	return fieldRefError(field, s.code, s.msg+other, other, false)
+}
+
+// fieldRefError returns a *Error for a constraint relating field to the other
+// field. Times are marked with a "type" param, so messages can be translated
+// accordingly.
+func fieldRefError(field, code, msg, other string, isTime bool) *Error {
+	params := map[string]interface{}{"other": FieldRef(other)}
+	if isTime {
+		params["type"] = "time"
+	}
+
+	return &Error{Field: field, Code: code, Params: params, Msg: msg}
+}
+
+// lengthMsg returns a message for a length constraint with the given quantity,
+// like "must be at least 3 characters long" for strings, or "must contain at
+// least 3 items" for collections. The unit is pluralized unless n is 1.
+func lengthMsg(v reflect.Value, qty string, n int) string {
+	unit := "item"
+	if v.Kind() == reflect.String {
+		unit = "character"
+	}
+	if n != 1 {
+		unit += "s"
+	}
+
+	if v.Kind() == reflect.String {
+		return fmt.Sprintf("must be %s %s long", qty, unit)
+	}
+
+	return fmt.Sprintf("must contain %s %s", qty, unit)
+}
+
+// lengthError returns a error for a length constraint, with a code based on
+// the kind of v, and if it is too short or too long. The message is built by
+// lengthMsg from qty and n.
+func lengthError(
+	field string,
+	v reflect.Value,
+	short bool,
+	qty string,
+	n int,
+	params map[string]interface{},
+) *Error {
+	code := CodeTooLong
+	switch {
+	case v.Kind() == reflect.String && short:
+		code = CodeTooShort
+	case v.Kind() != reflect.String && short:
+		code = CodeTooFewItems
+	case v.Kind() != reflect.String:
+		code = CodeTooManyItems
+	}
+
+	return &Error{
+		Field:  field,
+		Code:   code,
+		Params: params,
+		Msg:    lengthMsg(v, qty, n),
+	}
+}
+
+// oneOfError returns a error for a one-of constraint with the given options.
+func oneOfError(field string, options []interface{}) *Error {
+	strs := make([]string, 0, len(options))
+	for _, opt := range options {
+		strs = append(strs, fmt.Sprint(opt))
+	}
+
+	return &Error{
+		Field:  field,
+		Code:   CodeNotOneOf,
+		Params: map[string]interface{}{"options": options},
+		Msg:    "must be one of: " + strings.Join(strs, ", "),
+	}
+}
@@ -1,9 +1,14 @@
 package validate
 
 import (
+	"errors"
+	"math"
+	"regexp"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/multierr"
 )
 
 func stringPtr(s string) *string {
@@ -33,7 +38,9 @@ func TestRequireField(t *testing.T) {
 				field: "Title",
 				value: nil,
 			},
-			want: &Error{Field: "Title", Msg: "is required"},
+			want: &Error{
+				Field: "Title", Code: CodeRequired, Msg: "is required",
+			},
 		},
 		{
 			name: "nil pointer",
@@ -41,7 +48,9 @@ func TestRequireField(t *testing.T) {
 				field: "Title",
 				value: &nilMapString,
 			},
-			want: &Error{Field: "Title", Msg: "is required"},
+			want: &Error{
+				Field: "Title", Code: CodeRequired, Msg: "is required",
+			},
 		},
 		{
 			name: "true boolean",
@@ -57,7 +66,7 @@ func TestRequireField(t *testing.T) {
 				field: "Book",
 				value: false,
 			},
-			want: &Error{Field: "Book", Msg: "is required"},
+			want: &Error{Field: "Book", Code: CodeRequired, Msg: "is required"},
 		},
 		{
 			name: "int",
@@ -73,7 +82,9 @@ func TestRequireField(t *testing.T) {
 				field: "Count",
 				value: int(0),
 			},
-			want: &Error{Field: "Count", Msg: "is required"},
+			want: &Error{
+				Field: "Count", Code: CodeRequired, Msg: "is required",
+			},
 		},
 		{
 			name: "int8",
@@ -89,7 +100,9 @@ func TestRequireField(t *testing.T) {
 				field: "Ticks",
 				value: int8(0),
 			},
-			want: &Error{Field: "Ticks", Msg: "is required"},
+			want: &Error{
+				Field: "Ticks", Code: CodeRequired, Msg: "is required",
+			},
 		},
 		{
 			name: "int16",
@@ -105,7 +118,9 @@ func TestRequireField(t *testing.T) {
 				field: "Ticks",
 				value: int16(0),
 			},
-			want: &Error{Field: "Ticks", Msg: "is required"},
+			want: &Error{
+				Field: "Ticks", Code: CodeRequired, Msg: "is required",
+			},
 		},
 		{
 			name: "int32",
@@ -121,7 +136,9 @@ func TestRequireField(t *testing.T) {
 				field: "Ticks",
 				value: int32(0),
 			},
-			want: &Error{Field: "Ticks", Msg: "is required"},
+			want: &Error{
+				Field: "Ticks", Code: CodeRequired, Msg: "is required",
+			},
 		},
 		{
 			name: "int64",
@@ -137,7 +154,9 @@ func TestRequireField(t *testing.T) {
 				field: "Ticks",
 				value: int64(0),
 			},
-			want: &Error{Field: "Ticks", Msg: "is required"},
+			want: &Error{
+				Field: "Ticks", Code: CodeRequired, Msg: "is required",
+			},
 		},
 		{
 			name: "zero uint",
@@ -145,7 +164,9 @@ func TestRequireField(t *testing.T) {
 				field: "Count",
 				value: uint(0),
 			},
-			want: &Error{Field: "Count", Msg: "is required"},
+			want: &Error{
+				Field: "Count", Code: CodeRequired, Msg: "is required",
+			},
 		},
 		{
 			name: "uint8",
@@ -161,7 +182,9 @@ func TestRequireField(t *testing.T) {
 				field: "Ticks",
 				value: uint8(0),
 			},
-			want: &Error{Field: "Ticks", Msg: "is required"},
+			want: &Error{
+				Field: "Ticks", Code: CodeRequired, Msg: "is required",
+			},
 		},
 		{
 			name: "uint16",
@@ -177,7 +200,9 @@ func TestRequireField(t *testing.T) {
 				field: "Ticks",
 				value: uint16(0),
 			},
-			want: &Error{Field: "Ticks", Msg: "is required"},
+			want: &Error{
+				Field: "Ticks", Code: CodeRequired, Msg: "is required",
+			},
 		},
 		{
 			name: "uint32",
@@ -193,7 +218,9 @@ func TestRequireField(t *testing.T) {
 				field: "Ticks",
 				value: uint32(0),
 			},
-			want: &Error{Field: "Ticks", Msg: "is required"},
+			want: &Error{
+				Field: "Ticks", Code: CodeRequired, Msg: "is required",
+			},
 		},
 		{
 			name: "uint64",
@@ -209,7 +236,9 @@ func TestRequireField(t *testing.T) {
 				field: "Ticks",
 				value: uint64(0),
 			},
-			want: &Error{Field: "Ticks", Msg: "is required"},
+			want: &Error{
+				Field: "Ticks", Code: CodeRequired, Msg: "is required",
+			},
 		},
 		{
 			name: "complex64",
@@ -225,7 +254,9 @@ func TestRequireField(t *testing.T) {
 				field: "Offset",
 				value: complex64(0),
 			},
-			want: &Error{Field: "Offset", Msg: "is required"},
+			want: &Error{
+				Field: "Offset", Code: CodeRequired, Msg: "is required",
+			},
 		},
 		{
 			name: "complex128",
@@ -241,7 +272,9 @@ func TestRequireField(t *testing.T) {
 				field: "Offset",
 				value: complex128(0),
 			},
-			want: &Error{Field: "Offset", Msg: "is required"},
+			want: &Error{
+				Field: "Offset", Code: CodeRequired, Msg: "is required",
+			},
 		},
 		{
 			name: "array",
@@ -257,7 +290,7 @@ func TestRequireField(t *testing.T) {
 				field: "List",
 				value: [3]string{},
 			},
-			want: &Error{Field: "List", Msg: "is required"},
+			want: &Error{Field: "List", Code: CodeRequired, Msg: "is required"},
 		},
 		{
 			name: "chan",
@@ -297,7 +330,9 @@ func TestRequireField(t *testing.T) {
 				field: "Lookup",
 				value: map[string]string{},
 			},
-			want: &Error{Field: "Lookup", Msg: "is required"},
+			want: &Error{
+				Field: "Lookup", Code: CodeRequired, Msg: "is required",
+			},
 		},
 		{
 			name: "empty map pointer",
@@ -305,7 +340,9 @@ func TestRequireField(t *testing.T) {
 				field: "Lookup",
 				value: &emptyMapString,
 			},
-			want: &Error{Field: "Lookup", Msg: "is required"},
+			want: &Error{
+				Field: "Lookup", Code: CodeRequired, Msg: "is required",
+			},
 		},
 		{
 			name: "nil map",
@@ -313,7 +350,9 @@ func TestRequireField(t *testing.T) {
 				field: "Lookup",
 				value: nilMapString,
 			},
-			want: &Error{Field: "Lookup", Msg: "is required"},
+			want: &Error{
+				Field: "Lookup", Code: CodeRequired, Msg: "is required",
+			},
 		},
 		{
 			name: "slice",
@@ -329,7 +368,7 @@ func TestRequireField(t *testing.T) {
 				field: "List",
 				value: []string{},
 			},
-			want: &Error{Field: "List", Msg: "is required"},
+			want: &Error{Field: "List", Code: CodeRequired, Msg: "is required"},
 		},
 		{
 			name: "string",
@@ -353,7 +392,7 @@ func TestRequireField(t *testing.T) {
 				field: "Book",
 				value: "",
 			},
-			want: &Error{Field: "Book", Msg: "is required"},
+			want: &Error{Field: "Book", Code: CodeRequired, Msg: "is required"},
 		},
 		{
 			name: "empty string pointer",
@@ -361,7 +400,7 @@ func TestRequireField(t *testing.T) {
 				field: "Book",
 				value: stringPtr(""),
 			},
-			want: &Error{Field: "Book", Msg: "is required"},
+			want: &Error{Field: "Book", Code: CodeRequired, Msg: "is required"},
 		},
 		{
 			name: "struct",
@@ -385,7 +424,9 @@ func TestRequireField(t *testing.T) {
 				field: "Thing",
 				value: testStruct{},
 			},
-			want: &Error{Field: "Thing", Msg: "is required"},
+			want: &Error{
+				Field: "Thing", Code: CodeRequired, Msg: "is required",
+			},
 		},
 		{
 			name: "empty struct pointer",
@@ -393,7 +434,9 @@ func TestRequireField(t *testing.T) {
 				field: "Thing",
 				value: &testStruct{},
 			},
-			want: &Error{Field: "Thing", Msg: "is required"},
+			want: &Error{
+				Field: "Thing", Code: CodeRequired, Msg: "is required",
+			},
 		},
 	}
 	for _, tt := range tests {
@@ -404,3 +447,840 @@ func TestRequireField(t *testing.T) {
 		})
 	}
 }
+
+type testLevel string
+
+func TestForbidField(t *testing.T) {
+	name := "foo"
+	empty := ""
+	want := &Error{Field: "ID", Code: CodeNotEmpty, Msg: "must be empty"}
+
+	assert.NoError(t, ForbidField("ID", nil))
+	assert.NoError(t, ForbidField("ID", ""))
+	assert.NoError(t, ForbidField("ID", 0))
+	assert.NoError(t, ForbidField("ID", &empty))
+	assert.NoError(t, ForbidField("ID", (*string)(nil)))
+	assert.NoError(t, ForbidField("ID", []string{}))
+	assert.Equal(t, want, ForbidField("ID", "foo"))
+	assert.Equal(t, want, ForbidField("ID", &name))
+	assert.Equal(t, want, ForbidField("ID", 42))
+	assert.Equal(t, want, ForbidField("ID", []string{"a"}))
+}
+
+func TestConditionalHelpers(t *testing.T) {
+	required := &Error{Field: "f", Code: CodeRequired, Msg: "is required"}
+	forbidden := &Error{Field: "f", Code: CodeNotEmpty, Msg: "must be empty"}
+	set := "x"
+	var unset *string
+
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{
+			name: "RequireFieldIf true and empty",
+			err:  RequireFieldIf("f", "", true),
+			want: required,
+		},
+		{
+			name: "RequireFieldIf true and set",
+			err:  RequireFieldIf("f", "a", true),
+		},
+		{
+			name: "RequireFieldIf false",
+			err:  RequireFieldIf("f", "", false),
+		},
+		{
+			name: "RequireFieldWith any set",
+			err:  RequireFieldWith("f", "", unset, &set, 0),
+			want: required,
+		},
+		{
+			name: "RequireFieldWith none set",
+			err:  RequireFieldWith("f", "", unset, "", 0),
+		},
+		{
+			name: "RequireFieldWith no others",
+			err:  RequireFieldWith("f", ""),
+		},
+		{
+			name: "RequireFieldWith value set",
+			err:  RequireFieldWith("f", 1, "a"),
+		},
+		{
+			name: "RequireFieldWithAll all set",
+			err:  RequireFieldWithAll("f", nil, "a", &set, []int{1}),
+			want: required,
+		},
+		{
+			name: "RequireFieldWithAll some set",
+			err:  RequireFieldWithAll("f", nil, "a", unset),
+		},
+		{
+			name: "RequireFieldWithAll no others",
+			err:  RequireFieldWithAll("f", nil),
+		},
+		{
+			name: "RequireFieldWithout any empty",
+			err:  RequireFieldWithout("f", "", "a", map[string]int{}),
+			want: required,
+		},
+		{
+			name: "RequireFieldWithout none empty",
+			err:  RequireFieldWithout("f", "", "a", 1),
+		},
+		{
+			name: "RequireFieldWithout no others",
+			err:  RequireFieldWithout("f", ""),
+		},
+		{
+			name: "RequireFieldWithoutAll all empty",
+			err:  RequireFieldWithoutAll("f", "", unset, ""),
+			want: required,
+		},
+		{
+			name: "RequireFieldWithoutAll some set",
+			err:  RequireFieldWithoutAll("f", "", unset, "a"),
+		},
+		{
+			name: "RequireFieldWithoutAll no others",
+			err:  RequireFieldWithoutAll("f", ""),
+		},
+		{
+			name: "ForbidFieldIf true and set",
+			err:  ForbidFieldIf("f", "a", true),
+			want: forbidden,
+		},
+		{
+			name: "ForbidFieldIf true and empty",
+			err:  ForbidFieldIf("f", "", true),
+		},
+		{
+			name: "ForbidFieldIf false",
+			err:  ForbidFieldIf("f", "a", false),
+		},
+		{
+			name: "ForbidFieldWith any set",
+			err:  ForbidFieldWith("f", &set, "", true),
+			want: forbidden,
+		},
+		{
+			name: "ForbidFieldWith none set",
+			err:  ForbidFieldWith("f", &set, "", false, unset),
+		},
+		{
+			name: "ForbidFieldWith value empty",
+			err:  ForbidFieldWith("f", unset, "a"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.want == nil {
+				assert.NoError(t, tt.err)
+
+				return
+			}
+			assert.Equal(t, tt.want, tt.err)
+		})
+	}
+}
+
+func TestMinLength(t *testing.T) {
+	type args struct {
+		field   string
+		value   interface{}
+		minimum int
+	}
+	tests := []struct {
+		name string
+		args args
+		want error
+	}{
+		{
+			name: "string",
+			args: args{field: "Name", value: "foo", minimum: 3},
+			want: nil,
+		},
+		{
+			name: "short string",
+			args: args{field: "Name", value: "fo", minimum: 3},
+			want: &Error{
+				Field:  "Name",
+				Code:   CodeTooShort,
+				Params: map[string]interface{}{"min": 3},
+				Msg:    "must be at least 3 characters long",
+			},
+		},
+		{
+			name: "multi-byte string",
+			args: args{field: "Name", value: "日本語", minimum: 3},
+			want: nil,
+		},
+		{
+			name: "string pointer",
+			args: args{field: "Name", value: stringPtr("fo"), minimum: 3},
+			want: &Error{
+				Field:  "Name",
+				Code:   CodeTooShort,
+				Params: map[string]interface{}{"min": 3},
+				Msg:    "must be at least 3 characters long",
+			},
+		},
+		{
+			name: "nil pointer",
+			args: args{field: "Name", value: (*string)(nil), minimum: 1},
+			want: &Error{
+				Field:  "Name",
+				Code:   CodeTooShort,
+				Params: map[string]interface{}{"min": 1},
+				Msg:    "must be at least 1 character long",
+			},
+		},
+		{
+			name: "slice",
+			args: args{field: "Tags", value: []string{"a"}, minimum: 2},
+			want: &Error{
+				Field:  "Tags",
+				Code:   CodeTooFewItems,
+				Params: map[string]interface{}{"min": 2},
+				Msg:    "must contain at least 2 items",
+			},
+		},
+		{
+			name: "map",
+			args: args{
+				field: "Labels", value: map[string]int{"a": 1}, minimum: 1,
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MinLength(tt.args.field, tt.args.value, tt.args.minimum)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMinLength_unsupported(t *testing.T) {
+	assert.PanicsWithValue(t,
+		"validate: MinLength: unsupported type int",
+		func() { _ = MinLength("Count", 5, 1) },
+	)
+}
+
+func TestMaxLength(t *testing.T) {
+	type args struct {
+		field   string
+		value   interface{}
+		maximum int
+	}
+	tests := []struct {
+		name string
+		args args
+		want error
+	}{
+		{
+			name: "string",
+			args: args{field: "Name", value: "foo", maximum: 3},
+			want: nil,
+		},
+		{
+			name: "long string",
+			args: args{field: "Name", value: "foobar", maximum: 3},
+			want: &Error{
+				Field:  "Name",
+				Code:   CodeTooLong,
+				Params: map[string]interface{}{"max": 3},
+				Msg:    "must be at most 3 characters long",
+			},
+		},
+		{
+			name: "array",
+			args: args{field: "Items", value: [2]int{}, maximum: 1},
+			want: &Error{
+				Field:  "Items",
+				Code:   CodeTooManyItems,
+				Params: map[string]interface{}{"max": 1},
+				Msg:    "must contain at most 1 item",
+			},
+		},
+		{
+			name: "nil slice",
+			args: args{field: "Items", value: []int(nil), maximum: 0},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MaxLength(tt.args.field, tt.args.value, tt.args.maximum)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLengthBetween(t *testing.T) {
+	type args struct {
+		field   string
+		value   interface{}
+		minimum int
+		maximum int
+	}
+	tests := []struct {
+		name string
+		args args
+		want error
+	}{
+		{
+			name: "within",
+			args: args{field: "Name", value: "foo", minimum: 3, maximum: 64},
+			want: nil,
+		},
+		{
+			name: "too short",
+			args: args{field: "Name", value: "fo", minimum: 3, maximum: 64},
+			want: &Error{
+				Field:  "Name",
+				Code:   CodeTooShort,
+				Params: map[string]interface{}{"min": 3, "max": 64},
+				Msg:    "must be between 3 and 64 characters long",
+			},
+		},
+		{
+			name: "too many items",
+			args: args{
+				field: "Tags", value: []int{1, 2, 3}, minimum: 1, maximum: 2,
+			},
+			want: &Error{
+				Field:  "Tags",
+				Code:   CodeTooManyItems,
+				Params: map[string]interface{}{"min": 1, "max": 2},
+				Msg:    "must contain between 1 and 2 items",
+			},
+		},
+		{
+			name: "exact",
+			args: args{field: "Code", value: "abcd", minimum: 3, maximum: 3},
+			want: &Error{
+				Field:  "Code",
+				Code:   CodeTooLong,
+				Params: map[string]interface{}{"min": 3, "max": 3},
+				Msg:    "must be exactly 3 characters long",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := LengthBetween(
+				tt.args.field, tt.args.value, tt.args.minimum, tt.args.maximum,
+			)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMinMaxBetween(t *testing.T) {
+	age := 17
+	var nilAge *int
+
+	tests := []struct {
+		name string
+		got  error
+		want error
+	}{
+		{
+			name: "min int",
+			got:  Min("Age", 18, 18),
+			want: nil,
+		},
+		{
+			name: "min int pointer",
+			got:  Min("Age", &age, 18),
+			want: &Error{
+				Field:  "Age",
+				Code:   CodeTooSmall,
+				Params: map[string]interface{}{"min": 18.0},
+				Msg:    "must be at least 18",
+			},
+		},
+		{
+			name: "min nil pointer",
+			got:  Min("Age", nilAge, 18),
+			want: nil,
+		},
+		{
+			name: "max float",
+			got:  Max("Score", 1.75, 1.5),
+			want: &Error{
+				Field:  "Score",
+				Code:   CodeTooLarge,
+				Params: map[string]interface{}{"max": 1.5},
+				Msg:    "must be at most 1.5",
+			},
+		},
+		{
+			name: "max uint",
+			got:  Max("Count", uint8(3), 10),
+			want: nil,
+		},
+		{
+			name: "between",
+			got:  Between("Port", 8080, 1, 65535),
+			want: nil,
+		},
+		{
+			name: "not between",
+			got:  Between("Port", 0, 1, 65535),
+			want: &Error{
+				Field:  "Port",
+				Code:   CodeTooSmall,
+				Params: map[string]interface{}{"min": 1.0, "max": 65535.0},
+				Msg:    "must be between 1 and 65535",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.got)
+		})
+	}
+}
+
+func TestMin_unsupported(t *testing.T) {
+	assert.PanicsWithValue(t,
+		"validate: Min: unsupported type string",
+		func() { _ = Min("Age", "18", 18) },
+	)
+}
+
+func TestOneOf(t *testing.T) {
+	tests := []struct {
+		name string
+		got  error
+		want error
+	}{
+		{
+			name: "string",
+			got:  OneOf("Role", "admin", "admin", "user"),
+			want: nil,
+		},
+		{
+			name: "invalid string",
+			got:  OneOf("Role", "root", "admin", "user"),
+			want: &Error{
+				Field: "Role",
+				Code:  CodeNotOneOf,
+				Params: map[string]interface{}{
+					"options": []interface{}{"admin", "user"},
+				},
+				Msg: "must be one of: admin, user",
+			},
+		},
+		{
+			name: "named type",
+			got:  OneOf("Level", testLevel("warn"), "info", "warn"),
+			want: nil,
+		},
+		{
+			name: "int",
+			got:  OneOf("Count", int64(2), 1, 2, 3),
+			want: nil,
+		},
+		{
+			name: "mismatched kind",
+			got:  OneOf("Count", 2, "2"),
+			want: &Error{
+				Field:  "Count",
+				Code:   CodeNotOneOf,
+				Params: map[string]interface{}{"options": []interface{}{"2"}},
+				Msg:    "must be one of: 2",
+			},
+		},
+		{
+			name: "pointer",
+			got:  OneOf("Name", stringPtr("bar"), "foo"),
+			want: &Error{
+				Field:  "Name",
+				Code:   CodeNotOneOf,
+				Params: map[string]interface{}{"options": []interface{}{"foo"}},
+				Msg:    "must be one of: foo",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.got)
+		})
+	}
+}
+
+func TestStringHelpers(t *testing.T) {
+	re := regexp.MustCompile(`^[a-z]+$`)
+
+	tests := []struct {
+		name string
+		got  error
+		want error
+	}{
+		{
+			name: "match regexp",
+			got:  MatchRegexp("Slug", "foo", re),
+			want: nil,
+		},
+		{
+			name: "no regexp match",
+			got:  MatchRegexp("Slug", "Foo", re),
+			want: &Error{
+				Field:  "Slug",
+				Code:   CodePatternMismatch,
+				Params: map[string]interface{}{"pattern": "^[a-z]+$"},
+				Msg:    "must match ^[a-z]+$",
+			},
+		},
+		{
+			name: "has prefix",
+			got:  HasPrefix("URL", "https://example.com", "https://"),
+			want: nil,
+		},
+		{
+			name: "missing prefix",
+			got:  HasPrefix("URL", "http://example.com", "https://"),
+			want: &Error{
+				Field:  "URL",
+				Code:   CodeMissingPrefix,
+				Params: map[string]interface{}{"prefix": "https://"},
+				Msg:    `must start with "https://"`,
+			},
+		},
+		{
+			name: "has suffix",
+			got:  HasSuffix("File", "foo.go", ".go"),
+			want: nil,
+		},
+		{
+			name: "missing suffix",
+			got:  HasSuffix("File", "foo.rs", ".go"),
+			want: &Error{
+				Field:  "File",
+				Code:   CodeMissingSuffix,
+				Params: map[string]interface{}{"suffix": ".go"},
+				Msg:    `must end with ".go"`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.got)
+		})
+	}
+}
+
+func TestUniqueItems(t *testing.T) {
+	dup := &Error{
+		Field: "Items",
+		Code:  CodeDuplicateItems,
+		Msg:   "must only contain unique items",
+	}
+
+	tests := []struct {
+		name  string
+		value interface{}
+		want  error
+	}{
+		{name: "unique", value: []string{"a", "b"}, want: nil},
+		{name: "duplicates", value: []string{"a", "b", "a"}, want: dup},
+		{name: "array", value: [3]int{1, 2, 1}, want: dup},
+		{name: "nil", value: []int(nil), want: nil},
+		{
+			name:  "non-comparable",
+			value: [][]int{{1}, {2}, {1}},
+			want:  dup,
+		},
+		{
+			name:  "non-comparable unique",
+			value: []map[string]int{{"a": 1}, {"a": 2}},
+			want:  nil,
+		},
+		{
+			name:  "interface with non-comparable items",
+			value: []interface{}{[]int{1}, []int{1}},
+			want:  dup,
+		},
+		{
+			name:  "interface with mixed items",
+			value: []interface{}{"a", []int{1}, 1, "a"},
+			want:  dup,
+		},
+		{
+			name: "struct with non-comparable interface field",
+			value: []struct{ V interface{} }{
+				{V: []int{1}}, {V: []int{2}},
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := UniqueItems("Items", tt.value)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNonNegativeDuration(t *testing.T) {
+	assert.NoError(t, NonNegativeDuration("Timeout", 0))
+	assert.NoError(t, NonNegativeDuration("Timeout", time.Second))
+	assert.Equal(t,
+		&Error{
+			Field: "Timeout", Code: CodeNegative, Msg: "must not be negative",
+		},
+		NonNegativeDuration("Timeout", -time.Second),
+	)
+}
+
+func TestFieldComparisons(t *testing.T) {
+	one, two := 1, 2
+	start := time.Date(2021, 8, 22, 10, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	zoned := start.In(time.FixedZone("CEST", 2*60*60))
+	ref := func(code, msg string) error {
+		return &Error{
+			Field:  "A",
+			Code:   code,
+			Params: map[string]interface{}{"other": FieldRef("B")},
+			Msg:    msg,
+		}
+	}
+	timeRef := func(code, msg string) error {
+		return &Error{
+			Field: "A",
+			Code:  code,
+			Params: map[string]interface{}{
+				"other": FieldRef("B"), "type": "time",
+			},
+			Msg: msg,
+		}
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{
+			name: "EqualField equal strings",
+			err:  EqualField("A", "foo", "B", "foo"),
+		},
+		{
+			name: "EqualField different strings",
+			err:  EqualField("A", "foo", "B", "bar"),
+			want: ref(CodeNotEqualField, "must be equal to B"),
+		},
+		{
+			name: "EqualField different numeric types",
+			err:  EqualField("A", int64(1), "B", &one),
+		},
+		{
+			name: "EqualField nil pointer and zero value",
+			err:  EqualField("A", (*string)(nil), "B", ""),
+		},
+		{
+			name: "EqualField same instant in other zone",
+			err:  EqualField("A", start, "B", zoned),
+		},
+		{
+			name: "NotEqualField equal",
+			err:  NotEqualField("A", "foo", "B", "foo"),
+			want: ref(CodeEqualField, "must not be equal to B"),
+		},
+		{
+			name: "NotEqualField different",
+			err:  NotEqualField("A", []int{1}, "B", []int{2}),
+		},
+		{
+			name: "LessField less",
+			err:  LessField("A", 1, "B", 2.5),
+		},
+		{
+			name: "LessField equal",
+			err:  LessField("A", &two, "B", 2),
+			want: ref(CodeNotLessField, "must be less than B"),
+		},
+		{
+			name: "LessField nil pointer",
+			err:  LessField("A", (*int)(nil), "B", 1),
+		},
+		{
+			name: "LessField durations",
+			err:  LessField("A", time.Minute, "B", time.Second),
+			want: ref(CodeNotLessField, "must be less than B"),
+		},
+		{
+			name: "LessField times",
+			err:  LessField("A", end, "B", start),
+			want: timeRef(CodeNotLessField, "must be before B"),
+		},
+		{
+			name: "LessOrEqualField equal",
+			err:  LessOrEqualField("A", 2, "B", uint8(2)),
+		},
+		{
+			name: "LessOrEqualField greater",
+			err:  LessOrEqualField("A", 3, "B", 2),
+			want: ref(
+				CodeNotLessOrEqualField, "must be less than or equal to B",
+			),
+		},
+		{
+			name: "LessOrEqualField times",
+			err:  LessOrEqualField("A", end, "B", start),
+			want: timeRef(CodeNotLessOrEqualField, "must not be after B"),
+		},
+		{
+			name: "GreaterField greater",
+			err:  GreaterField("A", end, "B", &start),
+		},
+		{
+			name: "GreaterField equal",
+			err:  GreaterField("A", 2, "B", 2),
+			want: ref(CodeNotGreaterField, "must be greater than B"),
+		},
+		{
+			name: "GreaterField times",
+			err:  GreaterField("A", start, "B", start),
+			want: timeRef(CodeNotGreaterField, "must be after B"),
+		},
+		{
+			name: "GreaterOrEqualField equal",
+			err:  GreaterOrEqualField("A", start, "B", start),
+		},
+		{
+			name: "GreaterOrEqualField less",
+			err:  GreaterOrEqualField("A", 1.5, "B", 2),
+			want: ref(
+				CodeNotGreaterOrEqualField,
+				"must be greater than or equal to B",
+			),
+		},
+		{
+			name: "GreaterOrEqualField times",
+			err:  GreaterOrEqualField("A", start, "B", end),
+			want: timeRef(CodeNotGreaterOrEqualField, "must not be before B"),
+		},
+		{
+			name: "EqualField integers beyond float64 precision",
+			err:  EqualField("A", int64(1<<53), "B", int64(1<<53+1)),
+			want: ref(CodeNotEqualField, "must be equal to B"),
+		},
+		{
+			name: "NotEqualField large IDs",
+			err:  NotEqualField("A", uint64(1<<63), "B", uint64(1<<63+1)),
+		},
+		{
+			name: "LessField max uint64",
+			err: LessField(
+				"A", uint64(math.MaxUint64-1), "B", uint64(math.MaxUint64),
+			),
+		},
+		{
+			name: "LessField negative signed and max uint64",
+			err:  LessField("A", int64(-1), "B", uint64(math.MaxUint64)),
+		},
+		{
+			name: "LessField max int64 and larger uint64",
+			err:  LessField("A", int64(math.MaxInt64), "B", uint64(1<<63)),
+		},
+		{
+			name: "GreaterField max uint64 and negative signed",
+			err:  GreaterField("A", uint64(math.MaxUint64), "B", -1),
+		},
+		{
+			name: "GreaterOrEqualField max int64",
+			err: GreaterOrEqualField(
+				"A", int64(math.MaxInt64-1), "B", int64(math.MaxInt64),
+			),
+			want: ref(
+				CodeNotGreaterOrEqualField,
+				"must be greater than or equal to B",
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.want == nil {
+				assert.NoError(t, tt.err)
+
+				return
+			}
+			assert.Equal(t, tt.want, tt.err)
+		})
+	}
+}
+
+func TestFieldComparisons_unsupported(t *testing.T) {
+	assert.PanicsWithValue(t,
+		"validate: LessField: cannot compare string with string",
+		func() { _ = LessField("A", "a", "B", "b") },
+	)
+	assert.PanicsWithValue(t,
+		"validate: GreaterField: cannot compare time.Time with int",
+		func() { _ = GreaterField("A", time.Now(), "B", 1) },
+	)
+}
+
+func TestWithMessage(t *testing.T) {
+	assert.NoError(t, WithMessage(nil, "is too short"))
+	assert.Equal(t,
+		&Error{
+			Field:  "Name",
+			Code:   CodeTooShort,
+			Params: map[string]interface{}{"min": 3},
+			Msg:    "is too short",
+		},
+		WithMessage(MinLength("Name", "fo", 3), "is too short"),
+	)
+
+	errs := Append(MinLength("Name", "fo", 3), Min("Age", 17, 18))
+	errs = Append(errs, errors.New("oops"))
+
+	got := multierr.Errors(WithMessage(errs, "is invalid"))
+
+	assert.Equal(t, []error{
+		&Error{
+			Field:  "Name",
+			Code:   CodeTooShort,
+			Params: map[string]interface{}{"min": 3},
+			Msg:    "is invalid",
+		},
+		&Error{
+			Field:  "Age",
+			Code:   CodeTooSmall,
+			Params: map[string]interface{}{"min": 18.0},
+			Msg:    "is invalid",
+		},
+		errors.New("oops"),
+	}, got)
+}
+
+func TestWithSeverity(t *testing.T) {
+	assert.NoError(t, WithSeverity(nil, SeverityWarning))
+
+	errs := Append(RequireField("Tag", ""), errors.New("oops"))
+
+	got := multierr.Errors(WithSeverity(errs, SeverityInfo))
+
+	assert.Equal(t, []error{
+		&Error{
+			Field:    "Tag",
+			Code:     CodeRequired,
+			Severity: SeverityInfo,
+			Msg:      "is required",
+		},
+		errors.New("oops"),
+	}, got)
+}
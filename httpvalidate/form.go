@@ -0,0 +1,233 @@
+package httpvalidate
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/romdo/go-validate"
+)
+
+var (
+	textUnmarshalerType = reflect.TypeOf(
+		(*encoding.TextUnmarshaler)(nil),
+	).Elem()
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// decodeValues decodes form or query values into the struct dst points to.
+// Values are matched to fields by the name returned by
+// validate.DefaultFieldName, and fields of embedded structs are treated as
+// fields of the outer struct. Fields without a matching value are left as is.
+func decodeValues(values url.Values, dst interface{}) error {
+	if err := checkDestination(dst); err != nil {
+		return err
+	}
+
+	v := reflect.ValueOf(dst).Elem()
+	if v.Kind() != reflect.Struct {
+		return &DecodeError{
+			Status: http.StatusInternalServerError,
+			Err: fmt.Errorf(
+				"httpvalidate: cannot decode values into %s", v.Type(),
+			),
+		}
+	}
+
+	_, err := decodeStruct(values, v)
+
+	return err
+}
+
+// checkDestination returns a *DecodeError with a 500 status if dst is not a
+// non-nil pointer, as that is a programming error rather than a bad request.
+func checkDestination(dst interface{}) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return &DecodeError{
+			Status: http.StatusInternalServerError,
+			Err:    errors.New("httpvalidate: destination must be a pointer"),
+		}
+	}
+
+	return nil
+}
+
+// decodeStruct decodes values into the fields of the struct v, and reports if
+// any field was set. Nil pointers to embedded structs are only allocated if
+// any of their fields are set.
+func decodeStruct(values url.Values, v reflect.Value) (bool, error) {
+	set := false
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		fv := v.Field(i)
+
+		if sf.Anonymous && indirectType(sf.Type).Kind() == reflect.Struct {
+			ok, err := decodeEmbedded(values, fv)
+			if err != nil {
+				return false, err
+			}
+			set = set || ok
+
+			continue
+		}
+
+		if sf.PkgPath != "" {
+			continue
+		}
+
+		name := validate.DefaultFieldName(sf)
+		vals, ok := values[name]
+		if name == "" || !ok || len(vals) == 0 {
+			continue
+		}
+
+		if err := setValue(fv, vals); err != nil {
+			var decErr *DecodeError
+			if errors.As(err, &decErr) {
+				return false, decErr
+			}
+
+			return false, invalidValueError(name, "", err)
+		}
+		set = true
+	}
+
+	return set, nil
+}
+
+// decodeEmbedded decodes values into the embedded struct, or pointer to a
+// struct v. Nil pointers are decoded into a new struct, which is only assigned
+// to v if any of its fields were set.
+func decodeEmbedded(values url.Values, v reflect.Value) (bool, error) {
+	if v.Kind() != reflect.Ptr {
+		return decodeStruct(values, v)
+	}
+
+	if !v.IsNil() {
+		return decodeStruct(values, v.Elem())
+	}
+
+	if !v.CanSet() {
+		return false, nil
+	}
+
+	p := reflect.New(v.Type().Elem())
+	set, err := decodeStruct(values, p.Elem())
+	if set && err == nil {
+		v.Set(p)
+	}
+
+	return set, err
+}
+
+// setValue sets v to the given values. Slices receive all values, while all
+// other types receive the last value.
+func setValue(v reflect.Value, vals []string) error {
+	if v.Kind() == reflect.Slice && !v.Addr().Type().Implements(
+		textUnmarshalerType,
+	) {
+		s := reflect.MakeSlice(v.Type(), len(vals), len(vals))
+		for i, val := range vals {
+			if err := setString(s.Index(i), val); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+
+		return nil
+	}
+
+	return setString(v, vals[len(vals)-1])
+}
+
+// setString parses s into v based on its type. Unsupported types yield a
+// *DecodeError with a 500 status.
+func setString(v reflect.Value, s string) error {
+	if v.Kind() == reflect.Ptr {
+		p := reflect.New(v.Type().Elem())
+		if err := setString(p.Elem(), s); err != nil {
+			return err
+		}
+		v.Set(p)
+
+		return nil
+	}
+
+	if v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerType) {
+		u := v.Addr().Interface().(encoding.TextUnmarshaler)
+
+		return u.UnmarshalText([]byte(s))
+	}
+
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return errors.New("must be a duration")
+		}
+		v.SetInt(int64(d))
+
+		return nil
+	}
+
+	switch v.Kind() { //nolint:exhaustive
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		if s == "" || s == "on" {
+			v.SetBool(s == "on")
+
+			return nil
+		}
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return errors.New("must be a boolean")
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return errors.New("must be an integer")
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return errors.New("must be a non-negative integer")
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return errors.New("must be a number")
+		}
+		v.SetFloat(n)
+	default:
+		// Fields of unsupported types are a mistake of the server, not the
+		// client.
+		return &DecodeError{
+			Status: http.StatusInternalServerError,
+			Err: fmt.Errorf(
+				"httpvalidate: cannot decode values into %s", v.Type(),
+			),
+		}
+	}
+
+	return nil
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t
+}
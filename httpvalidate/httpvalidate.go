@@ -0,0 +1,411 @@
+// Package httpvalidate decodes HTTP requests into Go values and validates them
+// with a validate.Validator, writing RFC 9457 Problem Details responses when
+// decoding or validation fails.
+//
+// Request bodies are decoded from JSON, URL-encoded forms, or multipart forms
+// based on their Content-Type, and requests with a GET, HEAD, DELETE, or
+// OPTIONS method are decoded from their query string. Form and query values are
+// mapped to struct fields using validate.DefaultFieldName, so fields are
+// matched by their json, yaml, or form tag, just like field names in
+// validation errors. Reported fields therefore match the request's wire format.
+//
+// A Binder can be used directly within handlers:
+//
+//  func createUser(w http.ResponseWriter, r *http.Request) {
+//      var req CreateUserRequest
+//      if !binder.Bind(w, r, &req) {
+//          return
+//      }
+//      ...
+//  }
+//
+// Or as middleware, which makes the decoded value available to the next
+// handler via Value:
+//
+//  mux.Handle("/users", binder.Middleware(func() interface{} {
+//      return &CreateUserRequest{}
+//  })(http.HandlerFunc(createUser)))
+//
+//  func createUser(w http.ResponseWriter, r *http.Request) {
+//      req := httpvalidate.Value(r.Context()).(*CreateUserRequest)
+//      ...
+//  }
+package httpvalidate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/romdo/go-validate"
+)
+
+// DefaultMaxBodySize is the default maximum size of request bodies in bytes.
+const DefaultMaxBodySize = 1 << 20
+
+// defaultMaxMemory is the maximum memory used to parse multipart forms, with
+// the remainder of files stored on disk.
+const defaultMaxMemory = 32 << 20
+
+// ErrorHandler writes a response for a error which occurred while binding a
+// request. The error is a *DecodeError if the request could not be decoded,
+// otherwise the error returned by the Validator.
+type ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+
+// CodeInvalidValue is the Code of errors listed in DecodeError.Errors for
+// request values which cannot be decoded into the type of their field.
+const CodeInvalidValue = "invalid_value"
+
+// DecodeError is returned when a request cannot be decoded. Status is the HTTP
+// status code which should be used to respond, like 400 Bad Request, or 415
+// Unsupported Media Type.
+type DecodeError struct {
+	Status int
+	Err    error
+
+	// Errors lists the invalid values of the request, if known, which are
+	// included in the errors member of Problem Details responses.
+	Errors []validate.ProblemError
+}
+
+func (s *DecodeError) Error() string {
+	return s.Err.Error()
+}
+
+func (s *DecodeError) Unwrap() error {
+	return s.Err
+}
+
+// Binder decodes and validates requests. It is safe for concurrent use.
+type Binder struct {
+	validator    *validate.Validator
+	maxBodySize  int64
+	errorHandler ErrorHandler
+}
+
+// Option configures a Binder.
+type Option func(*Binder)
+
+// WithValidator sets the Validator used to validate decoded values. By
+// default, a Validator created with validate.New and no options is used.
+func WithValidator(v *validate.Validator) Option {
+	return func(s *Binder) {
+		if v != nil {
+			s.validator = v
+		}
+	}
+}
+
+// WithMaxBodySize sets the maximum size of request bodies in bytes. Larger
+// requests are rejected with a 413 Request Entity Too Large response. The
+// default is DefaultMaxBodySize. A size of zero or less disables the limit.
+func WithMaxBodySize(n int64) Option {
+	return func(s *Binder) {
+		s.maxBodySize = n
+	}
+}
+
+// WithErrorHandler sets a custom ErrorHandler to write responses for decoding
+// and validation errors, instead of the default DefaultErrorHandler.
+func WithErrorHandler(h ErrorHandler) Option {
+	return func(s *Binder) {
+		if h != nil {
+			s.errorHandler = h
+		}
+	}
+}
+
+// New creates a new Binder configured with the given options.
+func New(opts ...Option) *Binder {
+	s := &Binder{
+		validator:    validate.New(),
+		maxBodySize:  DefaultMaxBodySize,
+		errorHandler: DefaultErrorHandler,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+var defaultBinder = New()
+
+// Bind decodes and validates r into dst using a Binder with default options.
+// See Binder.Bind for details.
+func Bind(w http.ResponseWriter, r *http.Request, dst interface{}) bool {
+	return defaultBinder.Bind(w, r, dst)
+}
+
+// Bind decodes r into dst, which must be a non-nil pointer, and validates it
+// with the request's context. If decoding or validation fails, a error
+// response is written to w and false is returned, in which case the handler
+// should return without writing a response itself.
+func (s *Binder) Bind(
+	w http.ResponseWriter,
+	r *http.Request,
+	dst interface{},
+) bool {
+	if err := s.decode(w, r, dst); err != nil {
+		s.errorHandler(w, r, err)
+
+		return false
+	}
+
+	if err := s.validator.ValidateContext(r.Context(), dst); err != nil {
+		s.errorHandler(w, r, err)
+
+		return false
+	}
+
+	return true
+}
+
+// Decode decodes r into dst, which must be a non-nil pointer, without
+// validating it. It returns a *DecodeError if r cannot be decoded.
+func (s *Binder) Decode(r *http.Request, dst interface{}) error {
+	return s.decode(nil, r, dst)
+}
+
+func (s *Binder) decode(
+	w http.ResponseWriter,
+	r *http.Request,
+	dst interface{},
+) error {
+	if err := checkDestination(dst); err != nil {
+		return err
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodDelete,
+		http.MethodOptions:
+		return decodeValues(r.URL.Query(), dst)
+	}
+
+	if s.maxBodySize > 0 && r.Body != nil {
+		r.Body = http.MaxBytesReader(w, r.Body, s.maxBodySize)
+	}
+
+	ct := r.Header.Get("Content-Type")
+	mt, _, err := mime.ParseMediaType(ct)
+	if err != nil {
+		return &DecodeError{
+			Status: http.StatusUnsupportedMediaType,
+			Err:    fmt.Errorf("unsupported content type %q", ct),
+		}
+	}
+
+	switch {
+	case mt == "application/json" || hasJSONSuffix(mt):
+		return decodeJSON(r.Body, dst)
+	case mt == "application/x-www-form-urlencoded":
+		if err := r.ParseForm(); err != nil {
+			return bodyError(err)
+		}
+
+		return decodeValues(r.PostForm, dst)
+	case mt == "multipart/form-data":
+		if err := r.ParseMultipartForm(defaultMaxMemory); err != nil {
+			return bodyError(err)
+		}
+
+		return decodeValues(r.PostForm, dst)
+	}
+
+	return &DecodeError{
+		Status: http.StatusUnsupportedMediaType,
+		Err:    fmt.Errorf("unsupported content type %q", mt),
+	}
+}
+
+func hasJSONSuffix(mt string) bool {
+	return len(mt) > 5 && mt[len(mt)-5:] == "+json"
+}
+
+// decodeJSON decodes a single JSON value from body into dst.
+func decodeJSON(body io.Reader, dst interface{}) error {
+	if body == nil {
+		body = http.NoBody
+	}
+
+	dec := json.NewDecoder(body)
+	if err := dec.Decode(dst); err != nil {
+		if errors.Is(err, io.EOF) {
+			return &DecodeError{
+				Status: http.StatusBadRequest,
+				Err:    errors.New("request body must not be empty"),
+			}
+		}
+
+		return bodyError(err)
+	}
+
+	if dec.More() {
+		return &DecodeError{
+			Status: http.StatusBadRequest,
+			Err: errors.New(
+				"request body must only contain a single JSON value",
+			),
+		}
+	}
+
+	return nil
+}
+
+// bodyError returns a *DecodeError for a error which occurred while reading or
+// parsing a request body.
+func bodyError(err error) *DecodeError {
+	// http.MaxBytesReader does not return a distinct error type.
+	if err.Error() == "http: request body too large" {
+		return &DecodeError{
+			Status: http.StatusRequestEntityTooLarge,
+			Err:    errors.New("request body too large"),
+		}
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		msg := errors.New("must be " + jsonType(typeErr.Type))
+		if typeErr.Field == "" {
+			return &DecodeError{
+				Status: http.StatusBadRequest,
+				Err:    fmt.Errorf("invalid request body: %w", msg),
+			}
+		}
+
+		// Field is the dot separated path of JSON object keys and array
+		// indexes to the value, with object keys escaped like in JSON
+		// Pointers.
+		u := url.URL{
+			Fragment: "/" + strings.ReplaceAll(typeErr.Field, ".", "/"),
+		}
+
+		return invalidValueError(typeErr.Field, "#"+u.EscapedFragment(), msg)
+	}
+
+	return &DecodeError{
+		Status: http.StatusBadRequest,
+		Err:    fmt.Errorf("invalid request body: %w", err),
+	}
+}
+
+// invalidValueError returns a *DecodeError for the invalid value of the given
+// field, which is listed in the Errors of the returned error. Pointer is
+// optional.
+func invalidValueError(field, pointer string, err error) *DecodeError {
+	return &DecodeError{
+		Status: http.StatusBadRequest,
+		Err:    fmt.Errorf("invalid value for %s: %w", field, err),
+		Errors: []validate.ProblemError{{
+			Pointer: pointer,
+			Field:   field,
+			Code:    CodeInvalidValue,
+			Detail:  err.Error(),
+		}},
+	}
+}
+
+// jsonType describes the JSON values which can be decoded into the given type,
+// like "an array", without referring to Go types.
+func jsonType(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if reflect.PtrTo(t).Implements(textUnmarshalerType) {
+		return "a string"
+	}
+
+	switch t.Kind() { //nolint:exhaustive
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		return "an integer"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		return "a non-negative integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "a base64 encoded string"
+		}
+
+		return "an array"
+	case reflect.Array:
+		return "an array"
+	case reflect.Map, reflect.Struct:
+		return "an object"
+	}
+
+	return "a valid value"
+}
+
+// DefaultErrorHandler writes a RFC 9457 Problem Details response for err. A
+// *DecodeError yields a response with its status code listing its Errors, and
+// validation errors a 422 Unprocessable Entity response listing each error.
+// Any other errors, like the context being canceled, yield a 500 Internal
+// Server Error response.
+func DefaultErrorHandler(w http.ResponseWriter, _ *http.Request, err error) {
+	var decErr *DecodeError
+	switch {
+	case errors.As(err, &decErr):
+		p := newProblem(decErr.Status, decErr.Error())
+		p.Errors = decErr.Errors
+		_ = p.Write(w)
+	case errors.Is(err, context.Canceled),
+		errors.Is(err, context.DeadlineExceeded):
+		_ = newProblem(http.StatusInternalServerError, err.Error()).Write(w)
+	default:
+		_ = validate.WriteProblem(w, err)
+	}
+}
+
+func newProblem(status int, detail string) *validate.Problem {
+	return &validate.Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+}
+
+type valueContextKey struct{}
+
+// Middleware returns middleware which binds each request to a new value
+// returned by newDst, which must return a non-nil pointer. If binding
+// succeeds, the next handler is called with the value stored in the request's
+// context, which can be retrieved with Value. Otherwise a error response is
+// written, and the next handler is not called.
+func (s *Binder) Middleware(
+	newDst func() interface{},
+) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			dst := newDst()
+			if !s.Bind(w, r, dst) {
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), valueContextKey{}, dst)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// Value returns the value bound to the request by Binder.Middleware, or nil if
+// there is none.
+func Value(ctx context.Context) interface{} {
+	return ctx.Value(valueContextKey{})
+}
@@ -0,0 +1,572 @@
+package httpvalidate_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/romdo/go-validate"
+	"github.com/romdo/go-validate/httpvalidate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testAddress struct {
+	City string `json:"city"`
+}
+
+type testRequest struct {
+	Name    string         `json:"name"`
+	Age     int            `json:"age"`
+	Tags    []string       `json:"tags"`
+	Admin   bool           `json:"admin"`
+	Nick    *string        `json:"nick,omitempty"`
+	Timeout time.Duration  `json:"timeout"`
+	Ratio   float64        `json:"ratio"`
+	Address *testAddress   `json:"address"`
+	Skipped string         `json:"-"`
+	Extra   map[string]int `json:"extra"`
+}
+
+func (s *testRequest) Validate() error {
+	errs := validate.RequireField("Name", s.Name)
+	errs = validate.Append(errs, validate.Min("Age", s.Age, 18))
+
+	return errs
+}
+
+type testQuery struct {
+	testPaging
+
+	Search string   `form:"q"`
+	IDs    []uint   `form:"id"`
+	At     *testTS  `form:"at"`
+	Scores []string `form:"score"`
+}
+
+type testPaging struct {
+	Page  int `form:"page"`
+	Limit int `form:"limit"`
+}
+
+type testTS struct {
+	time.Time
+}
+
+func (s *testQuery) Validate() error {
+	return validate.Between("Limit", s.Limit, 1, 100)
+}
+
+func newJSONRequest(body string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	return r
+}
+
+func TestBinder_Bind_JSON(t *testing.T) {
+	r := newJSONRequest(`{
+		"name": "alice",
+		"age": 30,
+		"tags": ["a", "b"],
+		"admin": true,
+		"nick": "al",
+		"timeout": 5000000000,
+		"address": {"city": "Oslo"}
+	}`)
+	w := httptest.NewRecorder()
+	var got testRequest
+
+	ok := httpvalidate.New().Bind(w, r, &got)
+
+	require.True(t, ok)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	nick := "al"
+	assert.Equal(t, testRequest{
+		Name:    "alice",
+		Age:     30,
+		Tags:    []string{"a", "b"},
+		Admin:   true,
+		Nick:    &nick,
+		Timeout: 5 * time.Second,
+		Address: &testAddress{City: "Oslo"},
+	}, got)
+}
+
+func TestBinder_Bind_errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		binder      *httpvalidate.Binder
+		req         func() *http.Request
+		wantStatus  int
+		wantProblem string
+	}{
+		{
+			name:   "validation failure",
+			binder: httpvalidate.New(),
+			req: func() *http.Request {
+				return newJSONRequest(`{"age": 12}`)
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantProblem: `{
+				"type": "about:blank",
+				"title": "Unprocessable Entity",
+				"status": 422,
+				"detail": "2 validation error(s) found",
+				"errors": [
+					{
+						"pointer": "#/name",
+						"field": "name",
+						"code": "required",
+						"detail": "is required"
+					},
+					{
+						"pointer": "#/age",
+						"field": "age",
+						"code": "too_small",
+						"params": {"min": 18},
+						"detail": "must be at least 18"
+					}
+				]
+			}`,
+		},
+		{
+			name:   "invalid JSON",
+			binder: httpvalidate.New(),
+			req: func() *http.Request {
+				return newJSONRequest(`{"name": `)
+			},
+			wantStatus: http.StatusBadRequest,
+			wantProblem: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"detail": "invalid request body: unexpected EOF"
+			}`,
+		},
+		{
+			name:   "invalid JSON type",
+			binder: httpvalidate.New(),
+			req: func() *http.Request {
+				return newJSONRequest(`{"name": "alice", "age": "old"}`)
+			},
+			wantStatus: http.StatusBadRequest,
+			wantProblem: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"detail": "invalid value for age: must be an integer",
+				"errors": [
+					{
+						"pointer": "#/age",
+						"field": "age",
+						"code": "invalid_value",
+						"detail": "must be an integer"
+					}
+				]
+			}`,
+		},
+		{
+			name:   "invalid nested JSON type",
+			binder: httpvalidate.New(),
+			req: func() *http.Request {
+				return newJSONRequest(`{"address": {"city": ["Oslo"]}}`)
+			},
+			wantStatus: http.StatusBadRequest,
+			wantProblem: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"detail": "invalid value for address.city: must be a string",
+				"errors": [
+					{
+						"pointer": "#/address/city",
+						"field": "address.city",
+						"code": "invalid_value",
+						"detail": "must be a string"
+					}
+				]
+			}`,
+		},
+		{
+			name:   "invalid JSON array type",
+			binder: httpvalidate.New(),
+			req: func() *http.Request {
+				return newJSONRequest(`{"tags": {"a": 1}}`)
+			},
+			wantStatus: http.StatusBadRequest,
+			wantProblem: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"detail": "invalid value for tags: must be an array",
+				"errors": [
+					{
+						"pointer": "#/tags",
+						"field": "tags",
+						"code": "invalid_value",
+						"detail": "must be an array"
+					}
+				]
+			}`,
+		},
+		{
+			name:   "invalid JSON body type",
+			binder: httpvalidate.New(),
+			req: func() *http.Request {
+				return newJSONRequest(`[1, 2]`)
+			},
+			wantStatus: http.StatusBadRequest,
+			wantProblem: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"detail": "invalid request body: must be an object"
+			}`,
+		},
+		{
+			name:   "empty body",
+			binder: httpvalidate.New(),
+			req: func() *http.Request {
+				return newJSONRequest(``)
+			},
+			wantStatus: http.StatusBadRequest,
+			wantProblem: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"detail": "request body must not be empty"
+			}`,
+		},
+		{
+			name:   "multiple JSON values",
+			binder: httpvalidate.New(),
+			req: func() *http.Request {
+				return newJSONRequest(`{"name": "a", "age": 20} {}`)
+			},
+			wantStatus: http.StatusBadRequest,
+			wantProblem: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"detail": "request body must only contain a single JSON value"
+			}`,
+		},
+		{
+			name:   "unsupported content type",
+			binder: httpvalidate.New(),
+			req: func() *http.Request {
+				r := newJSONRequest(`name: alice`)
+				r.Header.Set("Content-Type", "application/yaml")
+
+				return r
+			},
+			wantStatus: http.StatusUnsupportedMediaType,
+			wantProblem: `{
+				"type": "about:blank",
+				"title": "Unsupported Media Type",
+				"status": 415,
+				"detail": "unsupported content type \"application/yaml\""
+			}`,
+		},
+		{
+			name:   "missing content type",
+			binder: httpvalidate.New(),
+			req: func() *http.Request {
+				r := newJSONRequest(`{}`)
+				r.Header.Del("Content-Type")
+
+				return r
+			},
+			wantStatus: http.StatusUnsupportedMediaType,
+			wantProblem: `{
+				"type": "about:blank",
+				"title": "Unsupported Media Type",
+				"status": 415,
+				"detail": "unsupported content type \"\""
+			}`,
+		},
+		{
+			name:   "body too large",
+			binder: httpvalidate.New(httpvalidate.WithMaxBodySize(16)),
+			req: func() *http.Request {
+				return newJSONRequest(`{"name": "alice", "age": 30}`)
+			},
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantProblem: `{
+				"type": "about:blank",
+				"title": "Request Entity Too Large",
+				"status": 413,
+				"detail": "request body too large"
+			}`,
+		},
+		{
+			name:   "invalid query value",
+			binder: httpvalidate.New(),
+			req: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/?age=old", nil)
+			},
+			wantStatus: http.StatusBadRequest,
+			wantProblem: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"detail": "invalid value for age: must be an integer",
+				"errors": [
+					{
+						"field": "age",
+						"code": "invalid_value",
+						"detail": "must be an integer"
+					}
+				]
+			}`,
+		},
+		{
+			name:   "canceled context",
+			binder: httpvalidate.New(),
+			req: func() *http.Request {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				return newJSONRequest(`{}`).WithContext(ctx)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantProblem: `{
+				"type": "about:blank",
+				"title": "Internal Server Error",
+				"status": 500,
+				"detail": "context canceled"
+			}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			var got testRequest
+
+			ok := tt.binder.Bind(w, tt.req(), &got)
+
+			assert.False(t, ok)
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t,
+				validate.ProblemContentType, w.Header().Get("Content-Type"),
+			)
+			assert.JSONEq(t, tt.wantProblem, w.Body.String())
+		})
+	}
+}
+
+func TestBinder_Bind_form(t *testing.T) {
+	form := url.Values{
+		"name":    {"alice"},
+		"age":     {"30"},
+		"tags":    {"a", "b"},
+		"admin":   {"on"},
+		"nick":    {"al"},
+		"timeout": {"1m30s"},
+		"ratio":   {"0.5"},
+		"Skipped": {"nope"},
+	}
+	r := httptest.NewRequest(
+		http.MethodPost, "/?age=99", strings.NewReader(form.Encode()),
+	)
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	var got testRequest
+
+	ok := httpvalidate.Bind(w, r, &got)
+
+	require.True(t, ok, w.Body.String())
+	nick := "al"
+	assert.Equal(t, testRequest{
+		Name:    "alice",
+		Age:     30,
+		Tags:    []string{"a", "b"},
+		Admin:   true,
+		Nick:    &nick,
+		Timeout: 90 * time.Second,
+		Ratio:   0.5,
+	}, got)
+}
+
+func TestBinder_Bind_multipartForm(t *testing.T) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	require.NoError(t, mw.WriteField("name", "bob"))
+	require.NoError(t, mw.WriteField("age", "12"))
+	require.NoError(t, mw.Close())
+
+	r := httptest.NewRequest(http.MethodPost, "/", &body)
+	r.Header.Set("Content-Type", mw.FormDataContentType())
+	w := httptest.NewRecorder()
+	var got testRequest
+
+	ok := httpvalidate.Bind(w, r, &got)
+
+	assert.False(t, ok)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, "bob", got.Name)
+	assert.Contains(t, w.Body.String(), `"field":"age"`)
+}
+
+func TestBinder_Bind_query(t *testing.T) {
+	r := httptest.NewRequest(
+		http.MethodGet,
+		"/?q=books&id=1&id=2&page=3&limit=50&at=2006-01-02T15:04:05Z",
+		nil,
+	)
+	w := httptest.NewRecorder()
+	var got testQuery
+
+	ok := httpvalidate.Bind(w, r, &got)
+
+	require.True(t, ok, w.Body.String())
+	assert.Equal(t, "books", got.Search)
+	assert.Equal(t, []uint{1, 2}, got.IDs)
+	assert.Equal(t, testPaging{Page: 3, Limit: 50}, got.testPaging)
+	require.NotNil(t, got.At)
+	assert.Equal(t,
+		time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC), got.At.Time.UTC(),
+	)
+	assert.Nil(t, got.Scores)
+
+	r = httptest.NewRequest(http.MethodGet, "/?limit=500", nil)
+	w = httptest.NewRecorder()
+
+	ok = httpvalidate.Bind(w, r, &testQuery{})
+
+	assert.False(t, ok)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), `"field":"limit"`)
+}
+
+func TestBinder_Decode_embeddedPointer(t *testing.T) {
+	type Paging testPaging
+	type search struct {
+		*Paging
+
+		Search string `form:"q"`
+	}
+	b := httpvalidate.New()
+
+	var got search
+	r := httptest.NewRequest(http.MethodGet, "/?q=books", nil)
+
+	require.NoError(t, b.Decode(r, &got))
+	assert.Equal(t, search{Search: "books"}, got)
+
+	got = search{}
+	r = httptest.NewRequest(http.MethodGet, "/?q=books&page=2", nil)
+
+	require.NoError(t, b.Decode(r, &got))
+	assert.Equal(t,
+		search{Paging: &Paging{Page: 2}, Search: "books"}, got,
+	)
+}
+
+func TestBinder_Decode_unsupportedField(t *testing.T) {
+	type search struct {
+		Filter testAddress `form:"filter"`
+	}
+	r := httptest.NewRequest(http.MethodGet, "/?filter=x", nil)
+
+	err := httpvalidate.New().Decode(r, &search{})
+
+	var decErr *httpvalidate.DecodeError
+	require.True(t, errors.As(err, &decErr))
+	assert.Equal(t, http.StatusInternalServerError, decErr.Status)
+	assert.Empty(t, decErr.Errors)
+	assert.EqualError(t, err,
+		"httpvalidate: cannot decode values into httpvalidate_test.testAddress",
+	)
+}
+
+func TestBinder_Decode(t *testing.T) {
+	b := httpvalidate.New()
+	var got testRequest
+
+	err := b.Decode(newJSONRequest(`{"age": 12}`), &got)
+
+	require.NoError(t, err)
+	assert.Equal(t, testRequest{Age: 12}, got)
+
+	for _, r := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/?name=x", nil),
+		newJSONRequest(`{"name": "x"}`),
+	} {
+		err = b.Decode(r, got)
+
+		var decErr *httpvalidate.DecodeError
+		require.True(t, errors.As(err, &decErr), r.Method)
+		assert.Equal(t, http.StatusInternalServerError, decErr.Status)
+		assert.EqualError(t, err,
+			"httpvalidate: destination must be a pointer",
+		)
+	}
+}
+
+func TestWithValidator(t *testing.T) {
+	v := validate.New(validate.WithPathFormatFunc(validate.JSONPath))
+	b := httpvalidate.New(httpvalidate.WithValidator(v))
+	w := httptest.NewRecorder()
+
+	ok := b.Bind(w, newJSONRequest(`{"name": "al", "age": 1}`), &testRequest{})
+
+	assert.False(t, ok)
+	assert.Contains(t, w.Body.String(), `"field":"age"`)
+}
+
+func TestWithErrorHandler(t *testing.T) {
+	var gotErr error
+	b := httpvalidate.New(httpvalidate.WithErrorHandler(
+		func(w http.ResponseWriter, _ *http.Request, err error) {
+			gotErr = err
+			w.WriteHeader(http.StatusTeapot)
+		},
+	))
+	w := httptest.NewRecorder()
+
+	ok := b.Bind(w, newJSONRequest(`{"name": "al"}`), &testRequest{})
+
+	assert.False(t, ok)
+	assert.Equal(t, http.StatusTeapot, w.Code)
+	assert.EqualError(t, gotErr, "age: must be at least 18")
+}
+
+func TestBinder_Middleware(t *testing.T) {
+	var got interface{}
+	calls := 0
+	h := httpvalidate.New().Middleware(func() interface{} {
+		return &testRequest{}
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		got = httpvalidate.Value(r.Context())
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newJSONRequest(`{"name": "alice", "age": 30}`))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, &testRequest{Name: "alice", Age: 30}, got)
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, newJSONRequest(`{"name": "alice"}`))
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, 1, calls)
+}
+
+func TestValue(t *testing.T) {
+	assert.Nil(t, httpvalidate.Value(context.Background()))
+}
@@ -0,0 +1,99 @@
+package validate
+
+// Option configures a Validator created with New.
+type Option func(*Validator)
+
+// WithFieldNameFunc sets a custom FieldNameFunc. It receives a
+// reflect.StructField, and must return a string for the name of that field. If
+// the returned string is empty, validation will not run against the field's
+// value, or any nested data within.
+func WithFieldNameFunc(f FieldNameFunc) Option {
+	return func(s *Validator) {
+		if f != nil {
+			s.fieldName = f
+		}
+	}
+}
+
+// WithFieldJoinFunc sets a custom FieldJoinFunc. It receives a string slice of
+// parent fields, and a string of the field name the error is reported against.
+// All parent paths, must be joined with the current.
+func WithFieldJoinFunc(f FieldJoinFunc) Option {
+	return func(s *Validator) {
+		if f != nil {
+			s.fieldJoin = f
+		}
+	}
+}
+
+// WithPathFormatFunc sets a PathFormatFunc used to render the Field value of
+// errors from their structured Path. When set, it takes precedence over the
+// FieldJoinFunc. JSONPointer and JSONPath are available as ready-made formats.
+func WithPathFormatFunc(f PathFormatFunc) Option {
+	return func(s *Validator) {
+		s.pathFormat = f
+	}
+}
+
+// WithReportCycles enables reporting of reference cycles as errors wrapping
+// ErrCycle, at the path where the cycle was detected.
+func WithReportCycles() Option {
+	return func(s *Validator) {
+		s.reportCycles = true
+	}
+}
+
+// WithMaxErrors limits the number of errors reported to n. Once n errors have
+// been found, validation stops without walking the remainder of the object. A
+// value of zero or less means no limit, which is the default. Warnings do not
+// count towards the limit.
+func WithMaxErrors(n int) Option {
+	return func(s *Validator) {
+		s.maxErrors = n
+	}
+}
+
+// WithFailFast stops validation after the first error is found. It is
+// equivalent to WithMaxErrors(1).
+func WithFailFast() Option {
+	return WithMaxErrors(1)
+}
+
+// WithStrict enables strict mode. In strict mode, values which implement
+// Validatable or ValidatableWithContext only via a pointer receiver, but which
+// cannot be validated as they are not addressable, like map values, are
+// reported as errors wrapping ErrNotAddressable.
+func WithStrict() Option {
+	return func(s *Validator) {
+		s.strict = true
+	}
+}
+
+// WithTagRules enables declarative validation rules in struct field tags, for
+// example `validate:"required,min=3,max=64"`. Rules are applied alongside any
+// Validate methods. See TagName for details.
+func WithTagRules() Option {
+	return func(s *Validator) {
+		s.tagRules = true
+	}
+}
+
+// WithRule registers a custom named rule which can be referenced from struct
+// field tags. See Validator.RegisterRule for details. Tag rules must also be
+// enabled with the WithTagRules option.
+func WithRule(name string, fn RuleFunc) Option {
+	return func(s *Validator) {
+		s.RegisterRule(name, fn)
+	}
+}
+
+// WithTranslator sets a Translator used to render the Msg of all reported
+// errors which have a Code, in the locale stored in the context passed to
+// ValidateContext with ContextWithLocale. Errors for which t has no message,
+// and errors with a customized Msg, like set with WithMessage, keep their
+// original Msg.
+func WithTranslator(t Translator) Option {
+	return func(s *Validator) {
+		s.translator = t
+	}
+}
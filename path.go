@@ -0,0 +1,335 @@
+package validate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// PathSegmentKind identifies the kind of a PathSegment.
+type PathSegmentKind int
+
+const (
+	// FieldSegment is a struct field.
+	FieldSegment PathSegmentKind = iota
+
+	// IndexSegment is an index of a slice or array.
+	IndexSegment
+
+	// KeySegment is a key of a map.
+	KeySegment
+)
+
+// String returns a textual representation of the kind.
+func (s PathSegmentKind) String() string {
+	switch s {
+	case FieldSegment:
+		return "field"
+	case IndexSegment:
+		return "index"
+	case KeySegment:
+		return "key"
+	}
+
+	return "unknown"
+}
+
+// MarshalText implements encoding.TextMarshaler, encoding the kind as its
+// textual representation.
+func (s PathSegmentKind) MarshalText() ([]byte, error) {
+	switch s {
+	case FieldSegment, IndexSegment, KeySegment:
+		return []byte(s.String()), nil
+	}
+
+	return nil, fmt.Errorf("validate: invalid path segment kind %d", int(s))
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, decoding kinds encoded by
+// MarshalText.
+func (s *PathSegmentKind) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "field":
+		*s = FieldSegment
+	case "index":
+		*s = IndexSegment
+	case "key":
+		*s = KeySegment
+	default:
+		return fmt.Errorf("validate: invalid path segment kind %q", text)
+	}
+
+	return nil
+}
+
+// PathSegment is a single component of the path to a value, relative to the
+// top-level object being validated.
+type PathSegment struct {
+	Kind PathSegmentKind
+
+	// Name is the Go name of the struct field for FieldSegment segments.
+	Name string
+
+	// Field is the display name of the struct field for FieldSegment segments,
+	// as returned by the Validator's FieldNameFunc.
+	Field string
+
+	// Index is the index for IndexSegment segments.
+	Index int
+
+	// Key is the original map key value for KeySegment segments.
+	Key interface{}
+}
+
+// jsonPathSegment is the JSON representation of a PathSegment.
+type jsonPathSegment struct {
+	Kind  PathSegmentKind `json:"kind"`
+	Name  string          `json:"name,omitempty"`
+	Field string          `json:"field,omitempty"`
+	Index *int            `json:"index,omitempty"`
+	Key   *string         `json:"key,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler. Only the members relevant to the
+// segment's kind are encoded, and map keys are encoded in their string form,
+// as used in rendered paths. Decoded key segments therefore always hold string
+// keys.
+func (s PathSegment) MarshalJSON() ([]byte, error) {
+	j := jsonPathSegment{Kind: s.Kind}
+	switch s.Kind {
+	case FieldSegment:
+		j.Name = s.Name
+		j.Field = s.Field
+	case IndexSegment:
+		j.Index = &s.Index
+	case KeySegment:
+		key := s.String()
+		j.Key = &key
+	}
+
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, decoding segments encoded by
+// MarshalJSON.
+func (s *PathSegment) UnmarshalJSON(data []byte) error {
+	var j jsonPathSegment
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	*s = PathSegment{Kind: j.Kind, Name: j.Name, Field: j.Field}
+	if j.Index != nil {
+		s.Index = *j.Index
+	}
+	if j.Key != nil {
+		s.Key = *j.Key
+	}
+
+	return nil
+}
+
+// String returns the segment's display name, index, or key as a string.
+func (s PathSegment) String() string {
+	switch s.Kind {
+	case IndexSegment:
+		return strconv.Itoa(s.Index)
+	case KeySegment:
+		return fmt.Sprintf("%v", s.Key)
+	case FieldSegment:
+	}
+
+	return s.Field
+}
+
+// pathStrings converts the given path to a string slice, suitable for passing
+// to a FieldJoinFunc.
+func pathStrings(path []PathSegment) []string {
+	if len(path) == 0 {
+		return nil
+	}
+
+	r := make([]string, 0, len(path))
+	for _, seg := range path {
+		r = append(r, seg.String())
+	}
+
+	return r
+}
+
+// joinPath returns a new path with the given segments appended to path.
+func joinPath(path []PathSegment, segs ...PathSegment) []PathSegment {
+	if len(path)+len(segs) == 0 {
+		return nil
+	}
+
+	r := make([]PathSegment, 0, len(path)+len(segs))
+	r = append(r, path...)
+
+	return append(r, segs...)
+}
+
+// resolveField resolves a dot-separated field path of Go field names, indexes,
+// and map keys, like "Containers.0.ImageRef", relative to values of type t.
+// Struct fields are resolved to their display names as returned by the
+// FieldNameFunc, and struct fields with an empty display name are omitted.
+// Any segments which cannot be resolved, are used as is.
+func (s *Validator) resolveField(t reflect.Type, field string) []PathSegment {
+	parts := strings.Split(field, ".")
+	segs := make([]PathSegment, 0, len(parts))
+
+	for _, part := range parts {
+		for t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		seg := PathSegment{Kind: FieldSegment, Name: part, Field: part}
+		var next reflect.Type
+
+		switch {
+		case t == nil:
+		case t.Kind() == reflect.Struct:
+			if nf, ok := s.typeInfo(t).byName[part]; ok {
+				seg.Field = nf.field
+				next = nf.typ
+			}
+		case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+			if i, err := strconv.Atoi(part); err == nil && i >= 0 {
+				seg = PathSegment{Kind: IndexSegment, Index: i}
+				next = t.Elem()
+			}
+		case t.Kind() == reflect.Map:
+			if key, ok := parseMapKey(part, t.Key()); ok {
+				seg = PathSegment{Kind: KeySegment, Key: key}
+				next = t.Elem()
+			}
+		}
+
+		if seg.Kind != FieldSegment || seg.Field != "" {
+			segs = append(segs, seg)
+		}
+		t = next
+	}
+
+	return segs
+}
+
+// parseMapKey converts s to a value of the given map key type. Only string,
+// integer, and unsigned integer key types are supported.
+func parseMapKey(s string, t reflect.Type) (interface{}, bool) {
+	v := reflect.New(t).Elem()
+
+	switch t.Kind() { //nolint:exhaustive
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, t.Bits())
+		if err != nil {
+			return nil, false
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		i, err := strconv.ParseUint(s, 10, t.Bits())
+		if err != nil {
+			return nil, false
+		}
+		v.SetUint(i)
+	case reflect.Interface:
+		return s, true
+	default:
+		return nil, false
+	}
+
+	return v.Interface(), true
+}
+
+// PathFormatFunc renders a path as a string, for use as the Field value of
+// errors.
+type PathFormatFunc func(path []PathSegment) string
+
+// jsonPointerEscaper escapes reference tokens as per RFC 6901.
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// JSONPointer is a PathFormatFunc which renders paths as RFC 6901 JSON
+// Pointers, for example "/items/1/book/author". An empty path yields an empty
+// string, which refers to the whole document.
+func JSONPointer(path []PathSegment) string {
+	var b strings.Builder
+	for _, seg := range path {
+		b.WriteByte('/')
+		b.WriteString(jsonPointerEscaper.Replace(seg.String()))
+	}
+
+	return b.String()
+}
+
+// JSONPath is a PathFormatFunc which renders paths in JSONPath style
+// dot/bracket notation, for example `items[1].book["weird.key"]`. Indexes are
+// rendered in brackets, map keys as quoted strings in brackets, and struct
+// fields with dot notation, unless their name is not a valid identifier.
+func JSONPath(path []PathSegment) string {
+	var b strings.Builder
+	for _, seg := range path {
+		switch seg.Kind {
+		case IndexSegment:
+			b.WriteByte('[')
+			b.WriteString(strconv.Itoa(seg.Index))
+			b.WriteByte(']')
+		case KeySegment:
+			b.WriteByte('[')
+			b.WriteString(quoteJSON(seg.String()))
+			b.WriteByte(']')
+		case FieldSegment:
+			if !isIdentifier(seg.Field) {
+				b.WriteByte('[')
+				b.WriteString(quoteJSON(seg.Field))
+				b.WriteByte(']')
+
+				continue
+			}
+
+			if b.Len() > 0 {
+				b.WriteByte('.')
+			}
+			b.WriteString(seg.Field)
+		}
+	}
+
+	return b.String()
+}
+
+// isIdentifier checks if s is valid for use with JSONPath dot notation.
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for i, r := range s {
+		switch {
+		case r == '_' || r == '$',
+			r >= 'a' && r <= 'z',
+			r >= 'A' && r <= 'Z',
+			i > 0 && r >= '0' && r <= '9':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// quoteJSON returns s as a quoted JSON string, without escaping HTML
+// characters.
+func quoteJSON(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+
+	return strings.TrimSuffix(buf.String(), "\n")
+}
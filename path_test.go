@@ -0,0 +1,448 @@
+package validate
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPathSegmentKind_String(t *testing.T) {
+	assert.Equal(t, "field", FieldSegment.String())
+	assert.Equal(t, "index", IndexSegment.String())
+	assert.Equal(t, "key", KeySegment.String())
+	assert.Equal(t, "unknown", PathSegmentKind(42).String())
+}
+
+func TestPathSegment_String(t *testing.T) {
+	tests := []struct {
+		name string
+		seg  PathSegment
+		want string
+	}{
+		{
+			name: "field",
+			seg:  PathSegment{Kind: FieldSegment, Name: "Foo", Field: "foo"},
+			want: "foo",
+		},
+		{
+			name: "index",
+			seg:  PathSegment{Kind: IndexSegment, Index: 3},
+			want: "3",
+		},
+		{
+			name: "string key",
+			seg:  PathSegment{Kind: KeySegment, Key: "weird.key"},
+			want: "weird.key",
+		},
+		{
+			name: "int key",
+			seg:  PathSegment{Kind: KeySegment, Key: 42},
+			want: "42",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.seg.String())
+		})
+	}
+}
+
+type testPathBook struct {
+	Title  string `json:"title"`
+	Author string
+}
+
+func (s *testPathBook) Validate() error {
+	return RequireField("Author", s.Author)
+}
+
+type testPathOrder struct {
+	Items   []*testPathItem          `json:"items"`
+	ByKey   map[string]*testPathItem `json:"by_key"`
+	Skipped string                   `json:"-"`
+}
+
+func (s *testPathOrder) Validate() error {
+	var errs error
+	errs = AppendFieldError(errs, "Skipped", "is not allowed")
+	errs = AppendError(errs, "is invalid")
+
+	return errs
+}
+
+type testPathItem struct {
+	Book *testPathBook `json:"book"`
+}
+
+func TestValidator_Validate_paths(t *testing.T) {
+	order := &testPathOrder{
+		Items: []*testPathItem{
+			{Book: &testPathBook{Author: "John Twelve Hawks"}},
+			{Book: &testPathBook{}},
+		},
+		ByKey: map[string]*testPathItem{
+			"1":         {Book: &testPathBook{}},
+			"weird.key": {Book: &testPathBook{}},
+		},
+	}
+
+	err := New().Validate(order)
+
+	bookAuthor := []PathSegment{
+		{Kind: FieldSegment, Name: "Book", Field: "book"},
+		{Kind: FieldSegment, Name: "Author", Field: "Author"},
+	}
+	assert.Equal(t, []error{
+		&Error{Field: "", Msg: "is not allowed"},
+		&Error{Field: "", Msg: "is invalid"},
+		&Error{
+			Field: "items.1.book.Author",
+			Path: append([]PathSegment{
+				{Kind: FieldSegment, Name: "Items", Field: "items"},
+				{Kind: IndexSegment, Index: 1},
+			}, bookAuthor...),
+			Code: CodeRequired, Msg: "is required",
+		},
+		&Error{
+			Field: "by_key.1.book.Author",
+			Path: append([]PathSegment{
+				{Kind: FieldSegment, Name: "ByKey", Field: "by_key"},
+				{Kind: KeySegment, Key: "1"},
+			}, bookAuthor...),
+			Code: CodeRequired, Msg: "is required",
+		},
+		&Error{
+			Field: "by_key.weird.key.book.Author",
+			Path: append([]PathSegment{
+				{Kind: FieldSegment, Name: "ByKey", Field: "by_key"},
+				{Kind: KeySegment, Key: "weird.key"},
+			}, bookAuthor...),
+			Code: CodeRequired, Msg: "is required",
+		},
+	}, Errors(err))
+}
+
+type testPathWrapper struct {
+	Inner *testPathItem `json:"inner"`
+}
+
+func (s *testPathWrapper) Validate() error {
+	// Errors from a nested validation run already have a Path, which is used
+	// relative to the current object.
+	return New().Validate(&testPathItem{Book: &testPathBook{}})
+}
+
+func TestValidator_Validate_nestedPaths(t *testing.T) {
+	err := New().Validate([]*testPathWrapper{{}})
+
+	assert.Equal(t, []error{
+		&Error{
+			Field: "0.book.Author",
+			Path: []PathSegment{
+				{Kind: IndexSegment, Index: 0},
+				{Kind: FieldSegment, Name: "Book", Field: "book"},
+				{Kind: FieldSegment, Name: "Author", Field: "Author"},
+			},
+			Code: CodeRequired, Msg: "is required",
+		},
+	}, Errors(err))
+}
+
+func TestJSONPointer(t *testing.T) {
+	tests := []struct {
+		name string
+		path []PathSegment
+		want string
+	}{
+		{name: "empty", path: nil, want: ""},
+		{
+			name: "nested",
+			path: []PathSegment{
+				{Kind: FieldSegment, Name: "Items", Field: "items"},
+				{Kind: IndexSegment, Index: 1},
+				{Kind: FieldSegment, Name: "Book", Field: "book"},
+				{Kind: FieldSegment, Name: "Author", Field: "author"},
+			},
+			want: "/items/1/book/author",
+		},
+		{
+			name: "escaped",
+			path: []PathSegment{
+				{Kind: FieldSegment, Name: "Labels", Field: "labels"},
+				{Kind: KeySegment, Key: "a/b~c.d"},
+			},
+			want: "/labels/a~1b~0c.d",
+		},
+		{
+			name: "empty key",
+			path: []PathSegment{
+				{Kind: KeySegment, Key: ""},
+			},
+			want: "/",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, JSONPointer(tt.path))
+		})
+	}
+}
+
+func TestJSONPath(t *testing.T) {
+	tests := []struct {
+		name string
+		path []PathSegment
+		want string
+	}{
+		{name: "empty", path: nil, want: ""},
+		{
+			name: "nested",
+			path: []PathSegment{
+				{Kind: FieldSegment, Name: "Items", Field: "items"},
+				{Kind: IndexSegment, Index: 1},
+				{Kind: FieldSegment, Name: "Book", Field: "book"},
+				{Kind: KeySegment, Key: "weird.key"},
+			},
+			want: `items[1].book["weird.key"]`,
+		},
+		{
+			name: "top-level index",
+			path: []PathSegment{
+				{Kind: IndexSegment, Index: 0},
+				{Kind: FieldSegment, Name: "Name", Field: "name"},
+			},
+			want: "[0].name",
+		},
+		{
+			name: "int key",
+			path: []PathSegment{
+				{Kind: FieldSegment, Name: "ByID", Field: "by_id"},
+				{Kind: KeySegment, Key: 42},
+			},
+			want: `by_id["42"]`,
+		},
+		{
+			name: "non-identifier field",
+			path: []PathSegment{
+				{Kind: FieldSegment, Name: "Spec", Field: "spec"},
+				{Kind: FieldSegment, Name: "Image", Field: "image-ref"},
+				{Kind: FieldSegment, Name: "X", Field: "_x$1"},
+			},
+			want: `spec["image-ref"]._x$1`,
+		},
+		{
+			name: "escaped key",
+			path: []PathSegment{
+				{Kind: KeySegment, Key: "say \"<hi>\""},
+			},
+			want: `["say \"<hi>\""]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, JSONPath(tt.path))
+		})
+	}
+}
+
+func TestWithPathFormatFunc(t *testing.T) {
+	order := &testPathOrder{
+		Items: []*testPathItem{{Book: &testPathBook{}}},
+		ByKey: map[string]*testPathItem{
+			"weird.key/x": {Book: &testPathBook{}},
+		},
+	}
+
+	tests := []struct {
+		name string
+		f    PathFormatFunc
+		want []string
+	}{
+		{
+			name: "json pointer",
+			f:    JSONPointer,
+			want: []string{
+				"",
+				"",
+				"/items/0/book/Author",
+				"/by_key/weird.key~1x/book/Author",
+			},
+		},
+		{
+			name: "json path",
+			f:    JSONPath,
+			want: []string{
+				"",
+				"",
+				"items[0].book.Author",
+				`by_key["weird.key/x"].book.Author`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := New(WithPathFormatFunc(tt.f)).Validate(order)
+
+			got := []string{}
+			for _, e := range Errors(err) {
+				got = append(got, e.(*Error).Field) //nolint:errorlint
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+type testResolveSpec struct {
+	Containers []*testResolveContainer         `json:"containers"`
+	ByName     map[string]testResolveContainer `json:"by_name"`
+	ByID       map[uint8]*testResolveContainer `json:"by_id"`
+	Any        interface{}                     `json:"any"`
+	Hidden     *testResolveContainer           `json:"-"`
+	Fixed      [2]testResolveContainer
+}
+
+type testResolveContainer struct {
+	Name     string `json:"name"`
+	ImageRef string `json:"imageRef"`
+}
+
+func TestValidator_resolveField(t *testing.T) {
+	containers := PathSegment{
+		Kind: FieldSegment, Name: "Containers", Field: "containers",
+	}
+	imageRef := PathSegment{
+		Kind: FieldSegment, Name: "ImageRef", Field: "imageRef",
+	}
+
+	tests := []struct {
+		name  string
+		field string
+		want  []PathSegment
+	}{
+		{
+			name:  "single field",
+			field: "Containers",
+			want:  []PathSegment{containers},
+		},
+		{
+			name:  "slice item field",
+			field: "Containers.0.ImageRef",
+			want: []PathSegment{
+				containers,
+				{Kind: IndexSegment, Index: 0},
+				imageRef,
+			},
+		},
+		{
+			name:  "array item field",
+			field: "Fixed.1.Name",
+			want: []PathSegment{
+				{Kind: FieldSegment, Name: "Fixed", Field: "Fixed"},
+				{Kind: IndexSegment, Index: 1},
+				{Kind: FieldSegment, Name: "Name", Field: "name"},
+			},
+		},
+		{
+			name:  "string map value field",
+			field: "ByName.web.ImageRef",
+			want: []PathSegment{
+				{Kind: FieldSegment, Name: "ByName", Field: "by_name"},
+				{Kind: KeySegment, Key: "web"},
+				imageRef,
+			},
+		},
+		{
+			name:  "uint map value field",
+			field: "ByID.7.ImageRef",
+			want: []PathSegment{
+				{Kind: FieldSegment, Name: "ByID", Field: "by_id"},
+				{Kind: KeySegment, Key: uint8(7)},
+				imageRef,
+			},
+		},
+		{
+			name:  "invalid uint map key",
+			field: "ByID.700.ImageRef",
+			want: []PathSegment{
+				{Kind: FieldSegment, Name: "ByID", Field: "by_id"},
+				{Kind: FieldSegment, Name: "700", Field: "700"},
+				{Kind: FieldSegment, Name: "ImageRef", Field: "ImageRef"},
+			},
+		},
+		{
+			name:  "invalid index",
+			field: "Containers.first.ImageRef",
+			want: []PathSegment{
+				containers,
+				{Kind: FieldSegment, Name: "first", Field: "first"},
+				{Kind: FieldSegment, Name: "ImageRef", Field: "ImageRef"},
+			},
+		},
+		{
+			name:  "unknown field",
+			field: "Images.0.Name",
+			want: []PathSegment{
+				{Kind: FieldSegment, Name: "Images", Field: "Images"},
+				{Kind: FieldSegment, Name: "0", Field: "0"},
+				{Kind: FieldSegment, Name: "Name", Field: "Name"},
+			},
+		},
+		{
+			name:  "interface field",
+			field: "Any.Name",
+			want: []PathSegment{
+				{Kind: FieldSegment, Name: "Any", Field: "any"},
+				{Kind: FieldSegment, Name: "Name", Field: "Name"},
+			},
+		},
+		{
+			name:  "field with empty display name",
+			field: "Hidden.Name",
+			want: []PathSegment{
+				{Kind: FieldSegment, Name: "Name", Field: "name"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := New()
+
+			got := v.resolveField(
+				reflect.TypeOf(&testResolveSpec{}), tt.field,
+			)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+type testResolveManifest struct {
+	Spec *testResolveSpec `json:"spec"`
+}
+
+func (s *testResolveManifest) Validate() error {
+	return AppendFieldError(nil, "Spec.Containers.1.ImageRef", "not found")
+}
+
+func TestValidator_Validate_nestedFieldNames(t *testing.T) {
+	err := New().Validate(map[string]*testResolveManifest{"app": {}})
+
+	assert.Equal(t, []error{
+		&Error{
+			Field: "app.spec.containers.1.imageRef",
+			Path: []PathSegment{
+				{Kind: KeySegment, Key: "app"},
+				{Kind: FieldSegment, Name: "Spec", Field: "spec"},
+				{
+					Kind: FieldSegment, Name: "Containers",
+					Field: "containers",
+				},
+				{Kind: IndexSegment, Index: 1},
+				{Kind: FieldSegment, Name: "ImageRef", Field: "imageRef"},
+			},
+			Msg: "not found",
+		},
+	}, Errors(err))
+}
@@ -0,0 +1,117 @@
+package validate
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ProblemContentType is the media type of RFC 9457 Problem Details documents.
+const ProblemContentType = "application/problem+json"
+
+// Problem is a RFC 9457 Problem Details document describing validation
+// failures, with a "errors" extension member listing each individual error.
+//
+// All standard members can be modified before the document is rendered, for
+// example to set Type to a URI documenting validation failures of a API.
+type Problem struct {
+	Type     string         `json:"type,omitempty"`
+	Title    string         `json:"title,omitempty"`
+	Status   int            `json:"status,omitempty"`
+	Detail   string         `json:"detail,omitempty"`
+	Instance string         `json:"instance,omitempty"`
+	Errors   []ProblemError `json:"errors,omitempty"`
+}
+
+// ProblemError describes a single validation error within a Problem.
+//
+// Pointer is a JSON Pointer to the invalid value in URI fragment form, like
+// "#/items/1/name", which is only set for errors with a Path. Field is the
+// error's Field as rendered by the Validator. Detail holds the error message.
+type ProblemError struct {
+	Pointer string                 `json:"pointer,omitempty"`
+	Field   string                 `json:"field,omitempty"`
+	Code    string                 `json:"code,omitempty"`
+	Params  map[string]interface{} `json:"params,omitempty"`
+	Detail  string                 `json:"detail"`
+}
+
+// NewProblem returns a Problem document describing the given error returned by
+// Validate, with a status of 422 Unprocessable Entity. All *Error values within
+// err are listed in the Errors member, while other errors are listed with only
+// their message as the detail. It returns nil if err is nil.
+func NewProblem(err error) *Problem {
+	errs := Errors(err)
+	if len(errs) == 0 {
+		return nil
+	}
+
+	p := &Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(http.StatusUnprocessableEntity),
+		Status: http.StatusUnprocessableEntity,
+		Detail: fmt.Sprintf("%d validation error(s) found", len(errs)),
+		Errors: make([]ProblemError, 0, len(errs)),
+	}
+	if len(errs) == 1 {
+		p.Detail = "1 validation error found"
+	}
+
+	for _, err := range errs {
+		p.Errors = append(p.Errors, newProblemError(err))
+	}
+
+	return p
+}
+
+func newProblemError(err error) ProblemError {
+	e, ok := err.(*Error) //nolint:errorlint
+	if !ok {
+		return ProblemError{Detail: err.Error()}
+	}
+
+	pe := ProblemError{
+		Field:  e.Field,
+		Code:   e.Code,
+		Params: e.Params,
+		Detail: e.message(),
+	}
+	if len(e.Path) > 0 {
+		u := url.URL{Fragment: JSONPointer(e.Path)}
+		pe.Pointer = "#" + u.EscapedFragment()
+	}
+
+	return pe
+}
+
+// WriteProblem writes a Problem document describing the given error returned
+// by Validate to w, with the Problem's status code and ProblemContentType as
+// the content type. It writes nothing and returns nil if err is nil.
+func WriteProblem(w http.ResponseWriter, err error) error {
+	p := NewProblem(err)
+	if p == nil {
+		return nil
+	}
+
+	return p.Write(w)
+}
+
+// Write writes the Problem document to w, with the Problem's status code and
+// ProblemContentType as the content type.
+func (s *Problem) Write(w http.ResponseWriter) error {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", ProblemContentType)
+	status := s.Status
+	if status == 0 {
+		status = http.StatusUnprocessableEntity
+	}
+	w.WriteHeader(status)
+	_, err = w.Write(b)
+
+	return err
+}
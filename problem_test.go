@@ -0,0 +1,115 @@
+package validate
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testProblemItem struct {
+	Name string `json:"name"`
+}
+
+func (s *testProblemItem) Validate() error {
+	return MinLength("Name", s.Name, 3)
+}
+
+type testProblemOrder struct {
+	Items map[string]*testProblemItem `json:"items"`
+}
+
+func (s *testProblemOrder) Validate() error {
+	errs := RequireField("Items", s.Items)
+
+	return Append(errs, errors.New("order is locked"))
+}
+
+func TestNewProblem(t *testing.T) {
+	assert.Nil(t, NewProblem(nil))
+
+	err := New().Validate(&testProblemOrder{
+		Items: map[string]*testProblemItem{"a/b c": {Name: "x"}},
+	})
+
+	got := NewProblem(err)
+
+	assert.Equal(t, &Problem{
+		Type:   "about:blank",
+		Title:  "Unprocessable Entity",
+		Status: 422,
+		Detail: "2 validation error(s) found",
+		Errors: []ProblemError{
+			{Detail: "order is locked"},
+			{
+				Pointer: "#/items/a~1b%20c/name",
+				Field:   "items.a/b c.name",
+				Code:    CodeTooShort,
+				Params:  map[string]interface{}{"min": 3},
+				Detail:  "must be at least 3 characters long",
+			},
+		},
+	}, got)
+}
+
+func TestNewProblem_single(t *testing.T) {
+	got := NewProblem(&Error{Err: errors.New("oops")})
+
+	assert.Equal(t, "1 validation error found", got.Detail)
+	assert.Equal(t, []ProblemError{{Detail: "oops"}}, got.Errors)
+}
+
+func TestWriteProblem(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	err := WriteProblem(w, New().Validate(&testProblemOrder{}))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{
+		"type": "about:blank",
+		"title": "Unprocessable Entity",
+		"status": 422,
+		"detail": "2 validation error(s) found",
+		"errors": [
+			{
+				"pointer": "#/items",
+				"field": "items",
+				"code": "required",
+				"detail": "is required"
+			},
+			{"detail": "order is locked"}
+		]
+	}`, w.Body.String())
+}
+
+func TestWriteProblem_nil(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	err := WriteProblem(w, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Body.String())
+}
+
+func TestProblem_Write(t *testing.T) {
+	w := httptest.NewRecorder()
+	p := &Problem{
+		Type:  "https://example.com/problems/validation",
+		Title: "Invalid request",
+	}
+
+	err := p.Write(w)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.JSONEq(t, `{
+		"type": "https://example.com/problems/validation",
+		"title": "Invalid request"
+	}`, w.Body.String())
+}
@@ -0,0 +1,19 @@
+package validate
+
+// Result holds the outcome of validating a object with Check, keeping errors
+// which cause validation to fail apart from warnings which do not.
+type Result struct {
+	// Errors holds all errors with SeverityError, combined into a single error
+	// in the same way as the error returned by Validate. It is nil if
+	// validation succeeded.
+	Errors error
+
+	// Warnings holds all errors with SeverityWarning or SeverityInfo,
+	// combined into a single error. Use Errors() to access them individually.
+	Warnings error
+}
+
+// Valid reports if validation succeeded, which may still be with warnings.
+func (s *Result) Valid() bool {
+	return s.Errors == nil
+}
@@ -0,0 +1,410 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"go.uber.org/multierr"
+)
+
+// TagName is the struct field tag which declares validation rules for a field
+// when tag rules are enabled with the WithTagRules option.
+const TagName = "validate"
+
+// scopeRule is the reserved rule name which scopes all following rules in a
+// tag to the given scenarios.
+const scopeRule = "on"
+
+// RuleFunc validates a single struct field against a rule declared in a
+// struct field tag. It receives the field's value, the rule's parameters, and
+// the struct containing the field. Pointer values are dereferenced, so value
+// is only a nil pointer if the field itself is a nil pointer.
+//
+// To report a validation failure, a error should be returned, typically a
+// *Error with Msg set. Like with Validate methods, multiple errors can be
+// combined with Append, and *Error values wrapped by other errors are
+// unwrapped. The Field and Path of returned errors are set to that of the
+// field being validated.
+type RuleFunc func(
+	value reflect.Value,
+	params []string,
+	parent reflect.Value,
+) error
+
+// rule is a parsed rule from a struct field tag.
+type rule struct {
+	name   string
+	params []string
+	fn     RuleFunc
+
+	// scenarios the rule is limited to, or nil if it applies to all.
+	scenarios []string
+}
+
+// builtinRules are the rules available to all Validators.
+var builtinRules = map[string]RuleFunc{
+	"required": ruleRequired,
+	"empty":    ruleEmpty,
+	"min":      ruleMin,
+	"max":      ruleMax,
+	"len":      ruleLen,
+	"oneof":    ruleOneOf,
+	"email":    stringRule(Email),
+	"url":      ruleURL,
+	"hostname": stringRule(Hostname),
+	"ipv4":     stringRule(IPv4),
+	"ipv6":     stringRule(IPv6),
+	"cidr":     stringRule(CIDR),
+	"uuid":     ruleUUID,
+	"rfc3339":  stringRule(RFC3339),
+	"iso8601":  stringRule(ISO8601),
+	"hexcolor": stringRule(HexColor),
+	"base64":   stringRule(Base64),
+	"semver":   stringRule(Semver),
+	"e164":     stringRule(E164),
+}
+
+// paramCheck checks the parameters of a rule declared in the tag of a struct
+// field of the given type, and panics if they are not valid.
+type paramCheck func(t reflect.Type, name string, params []string)
+
+// builtinParamChecks are the parameter checks of built-in rules, which are run
+// when tags are parsed, so invalid parameters are caught regardless of the
+// values being validated.
+var builtinParamChecks = map[string]paramCheck{
+	"min":  checkBoundParam,
+	"max":  checkBoundParam,
+	"len":  checkLengthParam,
+	"uuid": checkUUIDParam,
+}
+
+// RegisterRule registers a custom named rule which can be referenced from
+// struct field tags, or replaces a existing rule with the same name, including
+// built-in rules. Rule names must not be empty, must not contain commas, equals
+// signs, or whitespace, and must not be "on", which is reserved for scoping
+// rules to scenarios. RegisterRule panics if given a invalid name or a nil
+// function.
+//
+// RegisterRule is safe for concurrent use, and subsequent validation runs use
+// the new rule, while runs already in progress may still use the replaced rule.
+// Rules should typically be registered before the Validator is used.
+// Alternatively rules can be registered with the WithRule option when calling
+// New.
+func (s *Validator) RegisterRule(name string, fn RuleFunc) {
+	if name == "" || name == scopeRule ||
+		strings.ContainsAny(name, ",= \t\r\n") {
+		panic(fmt.Sprintf("validate: invalid rule name %q", name))
+	}
+
+	if fn == nil {
+		panic(fmt.Sprintf("validate: nil function for rule %q", name))
+	}
+
+	s.rulesMu.Lock()
+	defer s.rulesMu.Unlock()
+
+	s.rules[name] = fn
+
+	// Parameters of replaced built-in rules are up to the new function.
+	delete(s.paramChecks, name)
+
+	// Cached type metadata holds references to parsed rules. Bumping the
+	// generation prevents metadata built concurrently with the old rule from
+	// being cached after the reset.
+	s.rulesGen++
+	s.types.reset()
+}
+
+// rulesGeneration returns a counter which is incremented whenever a rule is
+// registered.
+func (s *Validator) rulesGeneration() uint64 {
+	s.rulesMu.RLock()
+	defer s.rulesMu.RUnlock()
+
+	return s.rulesGen
+}
+
+// rule returns the rule function registered with the given name, and the
+// check for its parameters, which is nil for custom rules.
+func (s *Validator) rule(name string) (RuleFunc, paramCheck, bool) {
+	s.rulesMu.RLock()
+	defer s.rulesMu.RUnlock()
+
+	fn, ok := s.rules[name]
+
+	return fn, s.paramChecks[name], ok
+}
+
+// parseRules parses the rules in the tag of the given struct field. Rules
+// following a "on=" scope are limited to the scenarios it lists. It panics if
+// the tag refers to a unknown rule, has a scope without scenarios, or has
+// invalid parameters for a built-in rule.
+func (s *Validator) parseRules(sf reflect.StructField) []rule {
+	if !s.tagRules {
+		return nil
+	}
+
+	tag := sf.Tag.Get(TagName)
+	if tag == "" || tag == "-" {
+		return nil
+	}
+
+	var rules []rule
+	var scenarios []string
+	for _, def := range strings.Split(tag, ",") {
+		def = strings.TrimSpace(def)
+		if def == "" {
+			continue
+		}
+
+		r := rule{name: def}
+		if i := strings.Index(def, "="); i >= 0 {
+			r.name = def[:i]
+			r.params = strings.Fields(def[i+1:])
+		}
+
+		if r.name == scopeRule {
+			if len(r.params) == 0 {
+				panic(fmt.Sprintf(
+					"validate: rule scope without scenarios in tag of "+
+						"field %s", sf.Name,
+				))
+			}
+			scenarios = r.params
+
+			continue
+		}
+		r.scenarios = scenarios
+
+		fn, check, ok := s.rule(r.name)
+		if !ok {
+			panic(fmt.Sprintf(
+				"validate: unknown rule %q in tag of field %s",
+				r.name, sf.Name,
+			))
+		}
+		if check != nil {
+			check(sf.Type, r.name, r.params)
+		}
+		r.fn = fn
+
+		rules = append(rules, r)
+	}
+
+	return rules
+}
+
+// isRequiredRule checks if the given rule must be applied to zero values.
+func isRequiredRule(name string) bool {
+	return name == "required"
+}
+
+// applyRules applies rules to the given struct field value. Unless the rules
+// include "required", all rules are skipped for zero values. Rules scoped to
+// scenarios are skipped unless the current scenario is one of them.
+func (s *walker) applyRules(
+	path []PathSegment,
+	rules []rule,
+	value reflect.Value,
+	parent reflect.Value,
+) {
+	for value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+
+	zero := isEmpty(value)
+	for _, r := range rules {
+		if s.done() {
+			return
+		}
+
+		if zero && !isRequiredRule(r.name) {
+			continue
+		}
+
+		if r.scenarios != nil && !containsString(r.scenarios, s.scenario) {
+			continue
+		}
+
+		for _, err := range multierr.Errors(r.fn(value, r.params, parent)) {
+			newErr := s.newError(path, err)
+			e := &Error{}
+			if errors.As(err, &e) {
+				newErr.Code = e.Code
+				newErr.Params = e.Params
+				newErr.Severity = e.Severity
+				newErr.Msg = e.Msg
+				newErr.Err = e.Err
+				s.resolveParams(parent.Type(), newErr)
+			}
+			s.report(newErr)
+		}
+	}
+}
+
+// ruleParamInt parses the single integer parameter of the named rule, and
+// panics if it is not valid.
+func ruleParamInt(name string, params []string) int {
+	if len(params) == 1 {
+		if n, err := strconv.Atoi(params[0]); err == nil {
+			return n
+		}
+	}
+
+	panic(fmt.Sprintf(
+		"validate: rule %q requires a single integer parameter", name,
+	))
+}
+
+// ruleParamFloat parses the single numeric parameter of the named rule, and
+// panics if it is not valid.
+func ruleParamFloat(name string, params []string) float64 {
+	if len(params) == 1 {
+		if n, err := strconv.ParseFloat(params[0], 64); err == nil {
+			return n
+		}
+	}
+
+	panic(fmt.Sprintf(
+		"validate: rule %q requires a single numeric parameter", name,
+	))
+}
+
+// checkBoundParam checks the parameter of the "min" and "max" rules, which
+// must be a integer for types with a length, and a number otherwise.
+func checkBoundParam(t reflect.Type, name string, params []string) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() { //nolint:exhaustive
+	case reflect.String, reflect.Array, reflect.Slice, reflect.Map:
+		ruleParamInt(name, params)
+	default:
+		ruleParamFloat(name, params)
+	}
+}
+
+// checkLengthParam checks the single integer parameter of the "len" rule.
+func checkLengthParam(_ reflect.Type, name string, params []string) {
+	ruleParamInt(name, params)
+}
+
+// checkUUIDParam checks the optional integer parameter of the "uuid" rule.
+func checkUUIDParam(_ reflect.Type, name string, params []string) {
+	if len(params) > 0 {
+		ruleParamInt(name, params)
+	}
+}
+
+func ruleRequired(v reflect.Value, _ []string, _ reflect.Value) error {
+	if isEmpty(v) {
+		return &Error{Code: CodeRequired, Msg: "is required"}
+	}
+
+	return nil
+}
+
+func ruleEmpty(v reflect.Value, _ []string, _ reflect.Value) error {
+	if !isEmpty(v) {
+		return &Error{Code: CodeNotEmpty, Msg: "must be empty"}
+	}
+
+	return nil
+}
+
+func ruleMin(v reflect.Value, params []string, _ reflect.Value) error {
+	if _, ok := length(v); ok {
+		return MinLength("", v.Interface(), ruleParamInt("min", params))
+	}
+
+	if _, ok := number(v); ok {
+		return Min("", v.Interface(), ruleParamFloat("min", params))
+	}
+
+	return nil
+}
+
+func ruleMax(v reflect.Value, params []string, _ reflect.Value) error {
+	if _, ok := length(v); ok {
+		return MaxLength("", v.Interface(), ruleParamInt("max", params))
+	}
+
+	if _, ok := number(v); ok {
+		return Max("", v.Interface(), ruleParamFloat("max", params))
+	}
+
+	return nil
+}
+
+func ruleLen(v reflect.Value, params []string, _ reflect.Value) error {
+	if _, ok := length(v); ok {
+		n := ruleParamInt("len", params)
+
+		return LengthBetween("", v.Interface(), n, n)
+	}
+
+	return nil
+}
+
+func ruleOneOf(v reflect.Value, params []string, _ reflect.Value) error {
+	s := fmt.Sprint(v.Interface())
+	for _, p := range params {
+		if s == p {
+			return nil
+		}
+	}
+
+	options := make([]interface{}, 0, len(params))
+	for _, p := range params {
+		options = append(options, p)
+	}
+
+	return oneOfError("", options)
+}
+
+func ruleURL(v reflect.Value, params []string, _ reflect.Value) error {
+	if v.Kind() != reflect.String {
+		return nil
+	}
+
+	return URL("", v.String(), params...)
+}
+
+func ruleUUID(v reflect.Value, params []string, _ reflect.Value) error {
+	if v.Kind() != reflect.String {
+		return nil
+	}
+
+	version := 0
+	if len(params) > 0 {
+		version = ruleParamInt("uuid", params)
+	}
+
+	return UUID("", v.String(), version)
+}
+
+// stringRule returns a RuleFunc which applies the given format helper to
+// string values, ignoring values of all other kinds.
+func stringRule(fn func(field string, value string) error) RuleFunc {
+	return func(v reflect.Value, _ []string, _ reflect.Value) error {
+		if v.Kind() != reflect.String {
+			return nil
+		}
+
+		return fn("", v.String())
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
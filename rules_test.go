@@ -0,0 +1,656 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testTagUser struct {
+	Name     string            `json:"name" validate:"required,min=3,max=8"`
+	Email    string            `json:"email" validate:"email"`
+	Nickname *string           `json:"nickname" validate:"min=2"`
+	Age      int               `json:"age" validate:"min=18,max=130"`
+	Score    float64           `json:"score" validate:"max=1.5"`
+	Role     string            `json:"role" validate:"oneof=admin user"`
+	Code     string            `json:"code" validate:"len=4"`
+	Tags     []string          `json:"tags" validate:"required,max=2"`
+	Labels   map[string]string `json:"labels" validate:"len=1"`
+	Ignored  string            `json:"-" validate:"required"`
+	Friends  []*testTagUser    `json:"friends"`
+
+	f func() error
+}
+
+func (s *testTagUser) Validate() error {
+	if s.f == nil {
+		return nil
+	}
+
+	return s.f()
+}
+
+func validTagUser() *testTagUser {
+	return &testTagUser{
+		Name:  "alice",
+		Email: "alice@example.com",
+		Age:   30,
+		Role:  "admin",
+		Code:  "abcd",
+		Tags:  []string{"a"},
+	}
+}
+
+func TestValidator_Validate_tagRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		obj      func() *testTagUser
+		wantErrs []error
+	}{
+		{
+			name: "disabled by default",
+			obj: func() *testTagUser {
+				return &testTagUser{}
+			},
+			wantErrs: []error{},
+		},
+		{
+			name:     "valid",
+			opts:     []Option{WithTagRules()},
+			obj:      validTagUser,
+			wantErrs: []error{},
+		},
+		{
+			name: "zero values",
+			opts: []Option{WithTagRules()},
+			obj: func() *testTagUser {
+				return &testTagUser{}
+			},
+			wantErrs: []error{
+				&Error{Field: "name", Code: CodeRequired, Msg: "is required"},
+				&Error{Field: "tags", Code: CodeRequired, Msg: "is required"},
+			},
+		},
+		{
+			name: "invalid values",
+			opts: []Option{WithTagRules()},
+			obj: func() *testTagUser {
+				nick := "x"
+
+				return &testTagUser{
+					Name:     "al",
+					Email:    "Alice <alice@example.com>",
+					Nickname: &nick,
+					Age:      12,
+					Score:    1.75,
+					Role:     "owner",
+					Code:     "abc",
+					Tags:     []string{"a", "b", "c"},
+					Labels:   map[string]string{"a": "1", "b": "2"},
+				}
+			},
+			wantErrs: []error{
+				&Error{
+					Field:  "name",
+					Code:   CodeTooShort,
+					Params: map[string]interface{}{"min": 3},
+					Msg:    "must be at least 3 characters long",
+				},
+				&Error{
+					Field:  "email",
+					Code:   CodeInvalidFormat,
+					Params: map[string]interface{}{"format": "email"},
+					Msg:    "must be a valid email address",
+				},
+				&Error{
+					Field:  "nickname",
+					Code:   CodeTooShort,
+					Params: map[string]interface{}{"min": 2},
+					Msg:    "must be at least 2 characters long",
+				},
+				&Error{
+					Field:  "age",
+					Code:   CodeTooSmall,
+					Params: map[string]interface{}{"min": 18.0},
+					Msg:    "must be at least 18",
+				},
+				&Error{
+					Field:  "score",
+					Code:   CodeTooLarge,
+					Params: map[string]interface{}{"max": 1.5},
+					Msg:    "must be at most 1.5",
+				},
+				&Error{
+					Field: "role",
+					Code:  CodeNotOneOf,
+					Params: map[string]interface{}{
+						"options": []interface{}{"admin", "user"},
+					},
+					Msg: "must be one of: admin, user",
+				},
+				&Error{
+					Field:  "code",
+					Code:   CodeTooShort,
+					Params: map[string]interface{}{"min": 4, "max": 4},
+					Msg:    "must be exactly 4 characters long",
+				},
+				&Error{
+					Field:  "tags",
+					Code:   CodeTooManyItems,
+					Params: map[string]interface{}{"max": 2},
+					Msg:    "must contain at most 2 items",
+				},
+				&Error{
+					Field:  "labels",
+					Code:   CodeTooManyItems,
+					Params: map[string]interface{}{"min": 1, "max": 1},
+					Msg:    "must contain exactly 1 item",
+				},
+			},
+		},
+		{
+			name: "nested with Validate method",
+			opts: []Option{WithTagRules()},
+			obj: func() *testTagUser {
+				u := validTagUser()
+				u.f = func() error {
+					return AppendFieldError(nil, "Name", "is taken")
+				}
+				friend := validTagUser()
+				friend.Name = "bobbybobbybob"
+				u.Friends = []*testTagUser{validTagUser(), friend}
+
+				return u
+			},
+			wantErrs: []error{
+				&Error{Field: "name", Msg: "is taken"},
+				&Error{
+					Field:  "friends.1.name",
+					Code:   CodeTooLong,
+					Params: map[string]interface{}{"max": 8},
+					Msg:    "must be at most 8 characters long",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := New(tt.opts...).Validate(tt.obj())
+
+			assert.Equal(t, tt.wantErrs, withoutPaths(Errors(err)))
+		})
+	}
+}
+
+func TestValidator_Validate_tagRulesPath(t *testing.T) {
+	err := New(WithTagRules()).Validate(
+		map[string]testTagUser{"x": *validTagUser(), "y": {}},
+	)
+
+	got := Errors(err)
+
+	assert.Len(t, got, 2)
+	assert.Equal(t, &Error{
+		Field: "y.name",
+		Path: []PathSegment{
+			{Kind: KeySegment, Key: "y"},
+			{Kind: FieldSegment, Name: "Name", Field: "name"},
+		},
+		Code: CodeRequired, Msg: "is required",
+	}, got[0])
+}
+
+type testTagFormats struct {
+	Website string  `json:"website" validate:"url=http https"`
+	Host    string  `json:"host" validate:"hostname"`
+	IP      string  `json:"ip" validate:"ipv4"`
+	Network string  `json:"network" validate:"cidr"`
+	ID      string  `json:"id" validate:"uuid=4"`
+	Created string  `json:"created" validate:"rfc3339"`
+	Color   *string `json:"color" validate:"hexcolor"`
+	Version string  `json:"version" validate:"semver"`
+	Phone   string  `json:"phone" validate:"e164"`
+	Count   int     `json:"count" validate:"email"`
+}
+
+func TestValidator_Validate_tagRulesFormats(t *testing.T) {
+	v := New(WithTagRules())
+	color := "#abcdef"
+	valid := &testTagFormats{
+		Website: "https://example.com",
+		Host:    "example.com",
+		IP:      "192.0.2.1",
+		Network: "192.0.2.0/24",
+		ID:      "f47ac10b-58cc-4372-a567-0e02b2c3d479",
+		Created: "2006-01-02T15:04:05Z",
+		Color:   &color,
+		Version: "1.2.3",
+		Phone:   "+14155552671",
+		Count:   3,
+	}
+
+	assert.NoError(t, v.Validate(&testTagFormats{}))
+	assert.NoError(t, v.Validate(valid))
+
+	badColor := "red"
+	err := v.Validate(&testTagFormats{
+		Website: "ftp://example.com",
+		Host:    "-example.com",
+		IP:      "::1",
+		Network: "192.0.2.0",
+		ID:      "123e4567-e89b-12d3-a456-426614174000",
+		Created: "2006-01-02",
+		Color:   &badColor,
+		Version: "v1",
+		Phone:   "555-1234",
+	})
+
+	got := withoutPaths(Errors(err))
+
+	assert.Equal(t, []error{
+		&Error{
+			Field: "website",
+			Code:  CodeInvalidFormat,
+			Params: map[string]interface{}{
+				"format": "url", "schemes": []string{"http", "https"},
+			},
+			Msg: "must be a valid URL with scheme http, https",
+		},
+		&Error{
+			Field:  "host",
+			Code:   CodeInvalidFormat,
+			Params: map[string]interface{}{"format": "hostname"},
+			Msg:    "must be a valid hostname",
+		},
+		&Error{
+			Field:  "ip",
+			Code:   CodeInvalidFormat,
+			Params: map[string]interface{}{"format": "ipv4"},
+			Msg:    "must be a valid IPv4 address",
+		},
+		&Error{
+			Field:  "network",
+			Code:   CodeInvalidFormat,
+			Params: map[string]interface{}{"format": "cidr"},
+			Msg:    "must be a valid CIDR notation",
+		},
+		&Error{
+			Field:  "id",
+			Code:   CodeInvalidFormat,
+			Params: map[string]interface{}{"format": "uuid", "version": 4},
+			Msg:    "must be a valid version 4 UUID",
+		},
+		&Error{
+			Field:  "created",
+			Code:   CodeInvalidFormat,
+			Params: map[string]interface{}{"format": "rfc3339"},
+			Msg:    "must be a valid RFC 3339 timestamp",
+		},
+		&Error{
+			Field:  "color",
+			Code:   CodeInvalidFormat,
+			Params: map[string]interface{}{"format": "hexcolor"},
+			Msg:    "must be a valid hex color",
+		},
+		&Error{
+			Field:  "version",
+			Code:   CodeInvalidFormat,
+			Params: map[string]interface{}{"format": "semver"},
+			Msg:    "must be a valid semantic version",
+		},
+		&Error{
+			Field:  "phone",
+			Code:   CodeInvalidFormat,
+			Params: map[string]interface{}{"format": "e164"},
+			Msg:    "must be a valid E.164 phone number",
+		},
+	}, got)
+}
+
+func TestValidator_Validate_tagRulesPanics(t *testing.T) {
+	tests := []struct {
+		name string
+		obj  interface{}
+		want string
+	}{
+		{
+			name: "unknown rule",
+			obj: &struct {
+				Name string `validate:"required,bogus=1"`
+			}{},
+			want: `validate: unknown rule "bogus" in tag of field Name`,
+		},
+		{
+			name: "invalid min parameter",
+			obj: &struct {
+				Name string `validate:"min=abc"`
+			}{},
+			want: `validate: rule "min" requires a single integer parameter`,
+		},
+		{
+			name: "invalid max parameter",
+			obj: &struct {
+				Age *int `validate:"max"`
+			}{},
+			want: `validate: rule "max" requires a single numeric parameter`,
+		},
+		{
+			name: "invalid len parameter",
+			obj: &struct {
+				Tags []string `validate:"len=1 2"`
+			}{},
+			want: `validate: rule "len" requires a single integer parameter`,
+		},
+		{
+			name: "invalid uuid parameter",
+			obj: &struct {
+				ID string `validate:"on=create,uuid=v4"`
+			}{},
+			want: `validate: rule "uuid" requires a single integer parameter`,
+		},
+		{
+			name: "scope without scenarios",
+			obj: &struct {
+				ID string `validate:"on=,required"`
+			}{},
+			want: `validate: rule scope without scenarios in tag of field ID`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.PanicsWithValue(t, tt.want, func() {
+				_ = New(WithTagRules()).Validate(tt.obj)
+			})
+		})
+	}
+}
+
+type testRuleProduct struct {
+	SKU      string `json:"sku" validate:"required,sku"`
+	Currency string `json:"currency" validate:"currency=USD EUR GBP"`
+	MinQty   int    `json:"min_qty"`
+	MaxQty   int    `json:"max_qty" validate:"gtefield=MinQty"`
+}
+
+func ruleSKU(v reflect.Value, _ []string, _ reflect.Value) error {
+	if !regexp.MustCompile(`^[A-Z]{3}-\d{4}$`).MatchString(v.String()) {
+		return &Error{Msg: "must be a valid SKU"}
+	}
+
+	return nil
+}
+
+func ruleCurrency(v reflect.Value, params []string, _ reflect.Value) error {
+	for _, p := range params {
+		if v.String() == p {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unsupported currency %q", v.String())
+}
+
+func ruleGTEField(
+	v reflect.Value,
+	params []string,
+	parent reflect.Value,
+) error {
+	other := parent.FieldByName(params[0])
+	if v.Int() < other.Int() {
+		return &Error{Msg: "must not be less than " + params[0]}
+	}
+
+	return nil
+}
+
+func TestValidator_RegisterRule(t *testing.T) {
+	product := &testRuleProduct{
+		SKU:      "abc-12",
+		Currency: "JPY",
+		MinQty:   10,
+		MaxQty:   5,
+	}
+	want := []error{
+		&Error{Field: "sku", Msg: "must be a valid SKU"},
+		&Error{
+			Field: "currency",
+			Err:   errors.New(`unsupported currency "JPY"`),
+		},
+		&Error{Field: "max_qty", Msg: "must not be less than MinQty"},
+	}
+
+	t.Run("method", func(t *testing.T) {
+		v := New(WithTagRules())
+		v.RegisterRule("sku", ruleSKU)
+		v.RegisterRule("currency", ruleCurrency)
+		v.RegisterRule("gtefield", ruleGTEField)
+
+		err := v.Validate(product)
+
+		assert.Equal(t, want, withoutPaths(Errors(err)))
+	})
+
+	t.Run("option", func(t *testing.T) {
+		v := New(
+			WithTagRules(),
+			WithRule("sku", ruleSKU),
+			WithRule("currency", ruleCurrency),
+			WithRule("gtefield", ruleGTEField),
+		)
+
+		err := v.Validate(product)
+
+		assert.Equal(t, want, withoutPaths(Errors(err)))
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		v := New(
+			WithTagRules(),
+			WithRule("sku", ruleSKU),
+			WithRule("currency", ruleCurrency),
+			WithRule("gtefield", ruleGTEField),
+		)
+
+		err := v.Validate(&testRuleProduct{
+			SKU: "ABC-1234", Currency: "EUR", MinQty: 1, MaxQty: 1,
+		})
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestValidator_RegisterRule_multipleErrors(t *testing.T) {
+	type account struct {
+		Password string `json:"password" validate:"strong"`
+		PIN      string `json:"pin" validate:"digits"`
+	}
+	v := New(
+		WithTagRules(),
+		WithRule("strong",
+			func(reflect.Value, []string, reflect.Value) error {
+				errs := Append(nil, &Error{Code: "no_digit", Msg: "no digit"})
+
+				return Append(errs, &Error{
+					Code:     "no_symbol",
+					Severity: SeverityWarning,
+					Msg:      "no symbol",
+				})
+			},
+		),
+		WithRule("digits",
+			func(reflect.Value, []string, reflect.Value) error {
+				return fmt.Errorf("pin: %w", &Error{
+					Code:   CodeInvalidFormat,
+					Params: map[string]interface{}{"format": "digits"},
+					Msg:    "must only contain digits",
+				})
+			},
+		),
+	)
+
+	res := v.Check(&account{Password: "secret", PIN: "12a4"})
+
+	assert.Equal(t, []error{
+		&Error{Field: "password", Code: "no_digit", Msg: "no digit"},
+		&Error{
+			Field:  "pin",
+			Code:   CodeInvalidFormat,
+			Params: map[string]interface{}{"format": "digits"},
+			Msg:    "must only contain digits",
+		},
+	}, withoutPaths(Errors(res.Errors)))
+	assert.Equal(t, []error{
+		&Error{
+			Field:    "password",
+			Code:     "no_symbol",
+			Severity: SeverityWarning,
+			Msg:      "no symbol",
+		},
+	}, withoutPaths(Errors(res.Warnings)))
+}
+
+func TestValidator_RegisterRule_override(t *testing.T) {
+	type user struct {
+		Name string `validate:"required"`
+	}
+	v := New(WithTagRules())
+	assert.Error(t, v.Validate(&user{}))
+
+	v.RegisterRule(
+		"required",
+		func(reflect.Value, []string, reflect.Value) error { return nil },
+	)
+
+	assert.NoError(t, v.Validate(&user{}))
+}
+
+func TestValidator_RegisterRule_overrideParams(t *testing.T) {
+	type user struct {
+		Name string `validate:"min=short"`
+	}
+	v := New(WithTagRules())
+	v.RegisterRule(
+		"min",
+		func(reflect.Value, []string, reflect.Value) error { return nil },
+	)
+
+	assert.NotPanics(t, func() {
+		assert.NoError(t, v.Validate(&user{Name: "a"}))
+	})
+}
+
+func TestValidator_RegisterRule_concurrent(t *testing.T) {
+	type user struct {
+		Name string `validate:"required"`
+	}
+	v := New(WithTagRules())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_ = v.Validate(&user{})
+		}()
+		go func() {
+			defer wg.Done()
+			v.RegisterRule("sku", ruleSKU)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestValidator_RegisterRule_duringAnalysis(t *testing.T) {
+	type product struct {
+		SKU string `validate:"required,sku"`
+
+		internal string
+	}
+	version := func(n int) RuleFunc {
+		return func(reflect.Value, []string, reflect.Value) error {
+			return &Error{Msg: fmt.Sprintf("version %d", n)}
+		}
+	}
+
+	// The FieldNameFunc is only called for unexported fields when resolving
+	// field names, after rules have been parsed. Replacing the rule at that
+	// point must not leave metadata with the old rule cached.
+	var v *Validator
+	n := 1
+	v = New(
+		WithTagRules(),
+		WithRule("sku", version(n)),
+		WithFieldNameFunc(func(sf reflect.StructField) string {
+			if sf.Name == "internal" && n < 3 {
+				n++
+				v.RegisterRule("sku", version(n))
+			}
+
+			return DefaultFieldName(sf)
+		}),
+	)
+
+	err := v.Validate(&product{SKU: "x", internal: "y"})
+
+	assert.EqualError(t, err, "SKU: version 3")
+	assert.EqualError(t, v.Validate(&product{SKU: "x"}), "SKU: version 3")
+}
+
+func TestValidator_RegisterRule_panics(t *testing.T) {
+	tests := []struct {
+		name     string
+		ruleName string
+		fn       RuleFunc
+		want     string
+	}{
+		{
+			name:     "empty name",
+			ruleName: "",
+			fn:       ruleSKU,
+			want:     `validate: invalid rule name ""`,
+		},
+		{
+			name:     "name with comma",
+			ruleName: "a,b",
+			fn:       ruleSKU,
+			want:     `validate: invalid rule name "a,b"`,
+		},
+		{
+			name:     "name with equals sign",
+			ruleName: "a=b",
+			fn:       ruleSKU,
+			want:     `validate: invalid rule name "a=b"`,
+		},
+		{
+			name:     "name with space",
+			ruleName: "a b",
+			fn:       ruleSKU,
+			want:     `validate: invalid rule name "a b"`,
+		},
+		{
+			name:     "reserved scope name",
+			ruleName: "on",
+			fn:       ruleSKU,
+			want:     `validate: invalid rule name "on"`,
+		},
+		{
+			name:     "nil function",
+			ruleName: "sku",
+			fn:       nil,
+			want:     `validate: nil function for rule "sku"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.PanicsWithValue(t, tt.want, func() {
+				New().RegisterRule(tt.ruleName, tt.fn)
+			})
+		})
+	}
+}
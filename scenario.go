@@ -0,0 +1,22 @@
+package validate
+
+import "context"
+
+type scenarioContextKey struct{}
+
+// ContextWithScenario returns a copy of ctx with the given scenario name, like
+// "create" or "update". When ctx is passed to ValidateContext, tag rules scoped
+// to other scenarios with "on=" are skipped, and ValidatableWithContext objects
+// can retrieve the scenario with ScenarioFromContext to decide which checks to
+// perform.
+func ContextWithScenario(ctx context.Context, scenario string) context.Context {
+	return context.WithValue(ctx, scenarioContextKey{}, scenario)
+}
+
+// ScenarioFromContext returns the scenario stored in ctx by
+// ContextWithScenario, or a empty string if none is set.
+func ScenarioFromContext(ctx context.Context) string {
+	scenario, _ := ctx.Value(scenarioContextKey{}).(string)
+
+	return scenario
+}
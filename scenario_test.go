@@ -0,0 +1,132 @@
+package validate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testScenarioUser struct {
+	ID   string `json:"id" validate:"on=create,empty,on=update patch,required"`
+	Name string `json:"name" validate:"max=8,on=create,required"`
+
+	Manager *testScenarioUser `json:"manager"`
+}
+
+func (s *testScenarioUser) ValidateContext(ctx context.Context) error {
+	if ScenarioFromContext(ctx) == "create" && s.Manager != nil {
+		return AppendFieldError(nil, "Manager", "cannot be set on create")
+	}
+
+	return nil
+}
+
+func TestValidator_ValidateContext_scenarios(t *testing.T) {
+	obj := &testScenarioUser{
+		ID:   "u1",
+		Name: "much too long",
+		Manager: &testScenarioUser{
+			Manager: &testScenarioUser{ID: "u3", Name: "bob"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		scenario string
+		want     []error
+	}{
+		{
+			name: "no scenario",
+			want: []error{
+				&Error{
+					Field:  "name",
+					Code:   CodeTooLong,
+					Params: map[string]interface{}{"max": 8},
+					Msg:    "must be at most 8 characters long",
+				},
+			},
+		},
+		{
+			name:     "create",
+			scenario: "create",
+			want: []error{
+				&Error{Field: "manager", Msg: "cannot be set on create"},
+				&Error{Field: "id", Code: CodeNotEmpty, Msg: "must be empty"},
+				&Error{
+					Field:  "name",
+					Code:   CodeTooLong,
+					Params: map[string]interface{}{"max": 8},
+					Msg:    "must be at most 8 characters long",
+				},
+				&Error{
+					Field: "manager.manager",
+					Msg:   "cannot be set on create",
+				},
+				&Error{
+					Field: "manager.name",
+					Code:  CodeRequired,
+					Msg:   "is required",
+				},
+				&Error{
+					Field: "manager.manager.id",
+					Code:  CodeNotEmpty,
+					Msg:   "must be empty",
+				},
+			},
+		},
+		{
+			name:     "update",
+			scenario: "update",
+			want: []error{
+				&Error{
+					Field:  "name",
+					Code:   CodeTooLong,
+					Params: map[string]interface{}{"max": 8},
+					Msg:    "must be at most 8 characters long",
+				},
+				&Error{
+					Field: "manager.id",
+					Code:  CodeRequired,
+					Msg:   "is required",
+				},
+			},
+		},
+		{
+			name:     "second scenario in scope",
+			scenario: "patch",
+			want: []error{
+				&Error{
+					Field:  "name",
+					Code:   CodeTooLong,
+					Params: map[string]interface{}{"max": 8},
+					Msg:    "must be at most 8 characters long",
+				},
+				&Error{
+					Field: "manager.id",
+					Code:  CodeRequired,
+					Msg:   "is required",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.scenario != "" {
+				ctx = ContextWithScenario(ctx, tt.scenario)
+			}
+
+			err := New(WithTagRules()).ValidateContext(ctx, obj)
+
+			assert.Equal(t, tt.want, withoutPaths(Errors(err)))
+		})
+	}
+}
+
+func TestScenarioFromContext(t *testing.T) {
+	assert.Equal(t, "", ScenarioFromContext(context.Background()))
+	assert.Equal(t, "create", ScenarioFromContext(
+		ContextWithScenario(context.Background(), "create"),
+	))
+}
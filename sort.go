@@ -0,0 +1,127 @@
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// SortKeyer can be implemented by map key types to control the order in which
+// map entries are validated, and hence the order of errors reported for them.
+type SortKeyer interface {
+	SortKey() string
+}
+
+var sortKeyerType = reflect.TypeOf((*SortKeyer)(nil)).Elem()
+
+// sortedMapKeys returns the keys of the given map value in a deterministic
+// order.
+func sortedMapKeys(m reflect.Value) []reflect.Value {
+	keys := m.MapKeys()
+	sort.SliceStable(keys, func(i, j int) bool {
+		return compareKeys(keys[i], keys[j]) < 0
+	})
+
+	return keys
+}
+
+// compareKeys compares two map keys, returning -1, 0, or +1. Keys implementing
+// SortKeyer are compared by their sort key, strings, numbers, and booleans are
+// compared by value, and all other keys by their fmt.Sprint representation.
+// Keys of different kinds, as found in maps with interface keys, are ordered by
+// their kind.
+func compareKeys(a, b reflect.Value) int {
+	if a.Kind() == reflect.Interface {
+		a = a.Elem()
+	}
+	if b.Kind() == reflect.Interface {
+		b = b.Elem()
+	}
+
+	if !a.IsValid() || !b.IsValid() {
+		return compareBools(a.IsValid(), b.IsValid())
+	}
+
+	if a.Type() == b.Type() && a.Type().Implements(sortKeyerType) {
+		return compareStrings(
+			a.Interface().(SortKeyer).SortKey(),
+			b.Interface().(SortKeyer).SortKey(),
+		)
+	}
+
+	if a.Kind() != b.Kind() {
+		return compareInts(int64(a.Kind()), int64(b.Kind()))
+	}
+
+	switch a.Kind() { //nolint:exhaustive
+	case reflect.String:
+		return compareStrings(a.String(), b.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		return compareInts(a.Int(), b.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		return compareUints(a.Uint(), b.Uint())
+	case reflect.Float32, reflect.Float64:
+		return compareFloats(a.Float(), b.Float())
+	case reflect.Bool:
+		return compareBools(a.Bool(), b.Bool())
+	}
+
+	return compareStrings(fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface()))
+}
+
+func compareStrings(a, b string) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+
+	return 0
+}
+
+func compareInts(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+
+	return 0
+}
+
+func compareUints(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+
+	return 0
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+
+	return 0
+}
+
+func compareBools(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case !a:
+		return -1
+	}
+
+	return 1
+}
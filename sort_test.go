@@ -0,0 +1,129 @@
+package validate
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testSortKey struct {
+	Name string
+	Rank int
+}
+
+func (s testSortKey) SortKey() string {
+	return string(rune('a' + s.Rank))
+}
+
+func TestSortedMapKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		m    interface{}
+		want []interface{}
+	}{
+		{
+			name: "strings",
+			m:    map[string]int{"b": 1, "c": 1, "a": 1, "aa": 1, "B": 1},
+			want: []interface{}{"B", "a", "aa", "b", "c"},
+		},
+		{
+			name: "ints",
+			m:    map[int]int{10: 1, -3: 1, 2: 1, 0: 1, 100: 1},
+			want: []interface{}{-3, 0, 2, 10, 100},
+		},
+		{
+			name: "uints",
+			m:    map[uint8]int{10: 1, 3: 1, 255: 1},
+			want: []interface{}{uint8(3), uint8(10), uint8(255)},
+		},
+		{
+			name: "floats",
+			m:    map[float64]int{1.5: 1, -2.25: 1, 0: 1},
+			want: []interface{}{-2.25, float64(0), 1.5},
+		},
+		{
+			name: "bools",
+			m:    map[bool]int{true: 1, false: 1},
+			want: []interface{}{false, true},
+		},
+		{
+			name: "sort keys",
+			m: map[testSortKey]int{
+				{Name: "x", Rank: 2}: 1,
+				{Name: "y", Rank: 0}: 1,
+				{Name: "z", Rank: 1}: 1,
+			},
+			want: []interface{}{
+				testSortKey{Name: "y", Rank: 0},
+				testSortKey{Name: "z", Rank: 1},
+				testSortKey{Name: "x", Rank: 2},
+			},
+		},
+		{
+			name: "structs",
+			m: map[struct{ A, B int }]int{
+				{A: 2, B: 1}: 1,
+				{A: 1, B: 2}: 1,
+			},
+			want: []interface{}{
+				struct{ A, B int }{A: 1, B: 2},
+				struct{ A, B int }{A: 2, B: 1},
+			},
+		},
+		{
+			name: "mixed interface keys",
+			m: map[interface{}]int{
+				"b": 1, 2: 1, "a": 1, 1: 1, nil: 1, true: 1,
+			},
+			want: []interface{}{nil, true, 1, 2, "a", "b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 10; i++ {
+				keys := sortedMapKeys(reflect.ValueOf(tt.m))
+
+				got := make([]interface{}, 0, len(keys))
+				for _, k := range keys {
+					got = append(got, k.Interface())
+				}
+
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestValidator_Validate_stableOrder(t *testing.T) {
+	newItem := func(msgs ...string) *testStruct {
+		return &testStruct{f: func() error {
+			var errs error
+			for _, msg := range msgs {
+				errs = AppendFieldError(errs, "Foo", msg)
+			}
+
+			return errs
+		}}
+	}
+	obj := map[string][]*testStruct{
+		"c": {newItem("c1"), newItem("c2", "c3")},
+		"a": {nil, newItem("a1")},
+		"b": {newItem("b1", "b2")},
+		"d": {},
+	}
+	want := []error{
+		&Error{Field: "a.1.foo", Msg: "a1"},
+		&Error{Field: "b.0.foo", Msg: "b1"},
+		&Error{Field: "b.0.foo", Msg: "b2"},
+		&Error{Field: "c.0.foo", Msg: "c1"},
+		&Error{Field: "c.1.foo", Msg: "c2"},
+		&Error{Field: "c.1.foo", Msg: "c3"},
+	}
+
+	for i := 0; i < 20; i++ {
+		err := New().Validate(obj)
+
+		assert.Equal(t, want, withoutPaths(Errors(err)))
+	}
+}
@@ -0,0 +1,398 @@
+package validate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultLocale is the locale of the messages bundled with Catalog, which is
+// also used as the fallback locale by catalogs created with NewCatalog.
+const DefaultLocale = "en"
+
+// Translator renders the message of a *Error in the given locale, based on the
+// error's Code and Params. It returns false if no message is available, in
+// which case the error's Msg is left as is.
+//
+// A Translator can be set on a Validator with the WithTranslator option, and
+// the locale used for a validation run is read from the context with
+// LocaleFromContext.
+type Translator interface {
+	Translate(locale string, err *Error) (string, bool)
+}
+
+type localeContextKey struct{}
+
+// ContextWithLocale returns a copy of ctx with the given locale, which is used
+// to translate error messages when ctx is passed to ValidateContext on a
+// Validator configured with a Translator.
+func ContextWithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeContextKey{}, locale)
+}
+
+// LocaleFromContext returns the locale stored in ctx by ContextWithLocale, or a
+// empty string if none is set.
+func LocaleFromContext(ctx context.Context) string {
+	locale, _ := ctx.Value(localeContextKey{}).(string)
+
+	return locale
+}
+
+// englishMessages holds the default English message templates for all codes
+// used by built-in helpers and tag rules. They render the same messages as the
+// helpers themselves.
+var englishMessages = map[string]string{
+	"required":      "is required",
+	"not_empty":     "must be empty",
+	"too_short":     "must be at least {min} characters long",
+	"too_short.one": "must be at least {min} character long",
+	"too_short.between": "must be between {min} " +
+		"and {max} characters long",
+	"too_short.between.one": "must be between {min} " +
+		"and {max} character long",
+	"too_short.exact":     "must be exactly {min} characters long",
+	"too_short.exact.one": "must be exactly {min} character long",
+	"too_long":            "must be at most {max} characters long",
+	"too_long.one":        "must be at most {max} character long",
+	"too_long.between": "must be between {min} " +
+		"and {max} characters long",
+	"too_long.between.one": "must be between {min} " +
+		"and {max} character long",
+	"too_long.exact":     "must be exactly {max} characters long",
+	"too_long.exact.one": "must be exactly {max} character long",
+	"too_few_items":      "must contain at least {min} items",
+	"too_few_items.one":  "must contain at least {min} item",
+	"too_few_items.between": "must contain between " +
+		"{min} and {max} items",
+	"too_few_items.between.one": "must contain between " +
+		"{min} and {max} item",
+	"too_few_items.exact":     "must contain exactly {min} items",
+	"too_few_items.exact.one": "must contain exactly {min} item",
+	"too_many_items":          "must contain at most {max} items",
+	"too_many_items.one":      "must contain at most {max} item",
+	"too_many_items.between": "must contain between " +
+		"{min} and {max} items",
+	"too_many_items.between.one": "must contain between " +
+		"{min} and {max} item",
+	"too_many_items.exact":     "must contain exactly {max} items",
+	"too_many_items.exact.one": "must contain exactly {max} item",
+	"too_small":                "must be at least {min}",
+	"too_small.between":        "must be between {min} and {max}",
+	"too_large":                "must be at most {max}",
+	"too_large.between":        "must be between {min} and {max}",
+	"not_one_of":               "must be one of: {options}",
+	"pattern_mismatch":         "must match {pattern}",
+	"missing_prefix":           "must start with \"{prefix}\"",
+	"missing_suffix":           "must end with \"{suffix}\"",
+	"duplicate_items":          "must only contain unique items",
+	"negative":                 "must not be negative",
+	"invalid_format":           "must be a valid {format}",
+	"invalid_format.email":     "must be a valid email address",
+	"invalid_format.url":       "must be a valid URL",
+	"invalid_format.url.schemes": "must be a valid " +
+		"URL with scheme {schemes}",
+	"invalid_format.hostname": "must be a valid hostname",
+	"invalid_format.ipv4":     "must be a valid IPv4 address",
+	"invalid_format.ipv6":     "must be a valid IPv6 address",
+	"invalid_format.cidr":     "must be a valid CIDR notation",
+	"invalid_format.uuid":     "must be a valid UUID",
+	"invalid_format.uuid.version": "must be a " +
+		"valid version {version} UUID",
+	"invalid_format.rfc3339":       "must be a valid RFC 3339 timestamp",
+	"invalid_format.iso8601":       "must be a valid ISO 8601 timestamp",
+	"invalid_format.hexcolor":      "must be a valid hex color",
+	"invalid_format.base64":        "must be valid base64",
+	"invalid_format.semver":        "must be a valid semantic version",
+	"invalid_format.e164":          "must be a valid E.164 phone number",
+	"not_equal_field":              "must be equal to {other}",
+	"equal_field":                  "must not be equal to {other}",
+	"not_less_field":               "must be less than {other}",
+	"not_less_field.time":          "must be before {other}",
+	"not_less_or_equal_field":      "must be less than or equal to {other}",
+	"not_less_or_equal_field.time": "must not be after {other}",
+	"not_greater_field":            "must be greater than {other}",
+	"not_greater_field.time":       "must be after {other}",
+	"not_greater_or_equal_field": "must be greater than or " +
+		"equal to {other}",
+	"not_greater_or_equal_field.time": "must not be before {other}",
+}
+
+// Catalog is a Translator which renders messages from templates keyed by locale
+// and message key. It is safe for concurrent use.
+//
+// Templates may refer to params of the error being translated with
+// placeholders like "{min}". Slice params are joined with commas.
+//
+// Message keys are based on the Code of errors, optionally followed by
+// dot-separated qualifiers, which allow selecting more specific templates:
+//
+//   - The value of the "format" param, like "invalid_format.email".
+//   - "between" if both "min" and "max" params are present, or "exact" if they
+//     are also equal. For "exact", a "between" template is used if no "exact"
+//     template is available.
+//   - "schemes" or "version" if the respective param is present, like
+//     "invalid_format.url.schemes".
+//   - The value of the "type" param, like "not_less_field.time".
+//   - "one" if the "max" param, or otherwise the "min" param, is equal to 1,
+//     like "too_short.one", for singular forms.
+//
+// The most specific available template is used, falling back to templates with
+// fewer qualifiers, and finally the plain code. If no template is found for the
+// locale, the base language of the locale is tried, like "pt" for "pt-BR",
+// followed by the catalog's fallback locale.
+type Catalog struct {
+	mu       sync.RWMutex
+	messages map[string]map[string]string
+	fallback string
+}
+
+var _ Translator = (*Catalog)(nil)
+
+// NewCatalog returns a new Catalog with the bundled English messages for all
+// built-in codes, using DefaultLocale as the fallback locale.
+func NewCatalog() *Catalog {
+	c := &Catalog{
+		messages: map[string]map[string]string{},
+		fallback: DefaultLocale,
+	}
+	c.Add(DefaultLocale, englishMessages)
+
+	return c
+}
+
+// SetFallback sets the locale used when no template is available in the
+// requested locale. A empty locale disables the fallback.
+func (s *Catalog) SetFallback(locale string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fallback = normalizeLocale(locale)
+}
+
+// Add adds the given message templates keyed by message key to the catalog
+// for the given locale, replacing existing templates with the same keys.
+func (s *Catalog) Add(locale string, messages map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	locale = normalizeLocale(locale)
+	m := s.messages[locale]
+	if m == nil {
+		m = map[string]string{}
+		s.messages[locale] = m
+	}
+
+	for k, v := range messages {
+		m[k] = v
+	}
+}
+
+// LoadJSON adds message templates for the given locale from r, which must
+// contain a JSON object mapping message keys to templates.
+func (s *Catalog) LoadJSON(locale string, r io.Reader) error {
+	var messages map[string]string
+	if err := json.NewDecoder(r).Decode(&messages); err != nil {
+		return fmt.Errorf(
+			"validate: failed to load %s catalog: %w", locale, err,
+		)
+	}
+	s.Add(locale, messages)
+
+	return nil
+}
+
+// LoadYAML adds message templates for the given locale from r, which must
+// contain a YAML mapping of message keys to templates.
+func (s *Catalog) LoadYAML(locale string, r io.Reader) error {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf(
+			"validate: failed to load %s catalog: %w", locale, err,
+		)
+	}
+
+	var messages map[string]string
+	if err := yaml.Unmarshal(b, &messages); err != nil {
+		return fmt.Errorf(
+			"validate: failed to load %s catalog: %w", locale, err,
+		)
+	}
+	s.Add(locale, messages)
+
+	return nil
+}
+
+// LoadFile adds message templates for the given locale from the JSON or YAML
+// file at path, based on its ".json", ".yaml", or ".yml" extension.
+func (s *Catalog) LoadFile(locale string, path string) error {
+	load := s.LoadJSON
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+	case ".yaml", ".yml":
+		load = s.LoadYAML
+	default:
+		return fmt.Errorf(
+			"validate: unsupported catalog file extension: %s", path,
+		)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf(
+			"validate: failed to load %s catalog: %w", locale, err,
+		)
+	}
+	defer f.Close()
+
+	return load(locale, f)
+}
+
+// Translate renders the message for err in the given locale. It returns false
+// if err has no Code, or no template is available.
+func (s *Catalog) Translate(locale string, err *Error) (string, bool) {
+	if err == nil || err.Code == "" {
+		return "", false
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := messageKeys(err)
+	for _, l := range s.locales(locale) {
+		m := s.messages[l]
+		for _, k := range keys {
+			if tmpl, ok := m[k]; ok {
+				return renderMessage(tmpl, err.Params), true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// locales returns the locales to look up templates in for the given locale, in
+// order of preference.
+func (s *Catalog) locales(locale string) []string {
+	locale = normalizeLocale(locale)
+
+	var r []string
+	if locale != "" {
+		r = append(r, locale)
+		if i := strings.Index(locale, "-"); i > 0 {
+			r = append(r, locale[:i])
+		}
+	}
+
+	if s.fallback != "" {
+		r = append(r, s.fallback)
+	}
+
+	return r
+}
+
+// normalizeLocale lower-cases locale and uses hyphens as separators, so
+// "pt_BR" and "pt-br" are equivalent to "pt-BR".
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+}
+
+// messageKeys returns the catalog keys to look up for err, from most to least
+// specific.
+func messageKeys(err *Error) []string {
+	base := []string{err.Code}
+	if f, ok := err.Params["format"].(string); ok && f != "" {
+		base = []string{err.Code + "." + f, err.Code}
+	}
+
+	var quals []string
+	minimum, hasMin := err.Params["min"]
+	maximum, hasMax := err.Params["max"]
+	switch {
+	case hasMin && hasMax && reflect.DeepEqual(minimum, maximum):
+		quals = append(quals, "exact", "between")
+	case hasMin && hasMax:
+		quals = append(quals, "between")
+	}
+	for _, name := range []string{"schemes", "version"} {
+		if _, ok := err.Params[name]; ok {
+			quals = append(quals, name)
+		}
+	}
+	if t, ok := err.Params["type"].(string); ok && t != "" {
+		quals = append(quals, t)
+	}
+
+	bound := minimum
+	if hasMax {
+		bound = maximum
+	}
+	one := fmt.Sprint(bound) == "1"
+
+	var keys []string
+	for _, b := range base {
+		for _, q := range append(quals, "") {
+			k := b
+			if q != "" {
+				k += "." + q
+			}
+			if one {
+				keys = append(keys, k+".one")
+			}
+			keys = append(keys, k)
+		}
+	}
+
+	return keys
+}
+
+// englishMessage renders the bundled English message for err, which is the
+// same as the default message of built-in helpers.
+func englishMessage(err *Error) (string, bool) {
+	for _, k := range messageKeys(err) {
+		if tmpl, ok := englishMessages[k]; ok {
+			return renderMessage(tmpl, err.Params), true
+		}
+	}
+
+	return "", false
+}
+
+// renderMessage replaces "{name}" placeholders in tmpl with the respective
+// values in params. Unknown placeholders are left as is.
+func renderMessage(tmpl string, params map[string]interface{}) string {
+	if len(params) == 0 || !strings.Contains(tmpl, "{") {
+		return tmpl
+	}
+
+	pairs := make([]string, 0, len(params)*2)
+	for k, v := range params {
+		pairs = append(pairs, "{"+k+"}", formatParam(v))
+	}
+
+	return strings.NewReplacer(pairs...).Replace(tmpl)
+}
+
+// formatParam formats a param value for use in messages. Slices and arrays
+// are joined with commas.
+func formatParam(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return fmt.Sprint(v)
+	}
+
+	items := make([]string, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		items = append(items, fmt.Sprint(rv.Index(i).Interface()))
+	}
+
+	return strings.Join(items, ", ")
+}
@@ -0,0 +1,334 @@
+package validate
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalog_Translate_english(t *testing.T) {
+	// The bundled English messages must render the same messages as helpers.
+	errs := []error{
+		RequireField("f", ""),
+		ForbidField("f", "a"),
+		MinLength("f", "ab", 3),
+		MinLength("f", "", 1),
+		MinLength("f", []int{}, 2),
+		MinLength("f", []int{}, 1),
+		MaxLength("f", "abcd", 3),
+		MaxLength("f", "ab", 1),
+		MaxLength("f", []int{1, 2}, 1),
+		LengthBetween("f", "ab", 3, 64),
+		LengthBetween("f", "ab", 0, 1),
+		LengthBetween("f", "abcd", 3, 3),
+		LengthBetween("f", "ab", 1, 1),
+		LengthBetween("f", []int{1, 2, 3}, 1, 2),
+		LengthBetween("f", []int{}, 2, 2),
+		LengthBetween("f", []int{1, 2}, 1, 1),
+		Min("f", 1, 18),
+		Min("f", 1, 1.5),
+		Max("f", 2, 1),
+		Between("f", 0, 1, 65535),
+		Between("f", 0, 5, 5),
+		OneOf("f", "c", "a", "b"),
+		OneOf("f", 3, 1, 2),
+		MatchRegexp("f", "A", regexp.MustCompile(`^[a-z]+$`)),
+		HasPrefix("f", "b", "a"),
+		HasSuffix("f", "b", "a"),
+		UniqueItems("f", []int{1, 1}),
+		NonNegativeDuration("f", -time.Second),
+		Email("f", ""),
+		URL("f", ""),
+		URL("f", "", "http", "https"),
+		Hostname("f", ""),
+		IPv4("f", ""),
+		IPv6("f", ""),
+		CIDR("f", ""),
+		UUID("f", "", 0),
+		UUID("f", "", 4),
+		RFC3339("f", ""),
+		ISO8601("f", ""),
+		HexColor("f", ""),
+		Base64("f", ""),
+		Semver("f", ""),
+		E164("f", ""),
+		EqualField("f", "a", "g", "b"),
+		NotEqualField("f", 1, "g", 1),
+		LessField("f", 2, "g", 1),
+		LessField("f", time.Unix(2, 0), "g", time.Unix(1, 0)),
+		LessOrEqualField("f", 2, "g", 1),
+		LessOrEqualField("f", time.Unix(2, 0), "g", time.Unix(1, 0)),
+		GreaterField("f", 1, "g", 2),
+		GreaterField("f", time.Unix(1, 0), "g", time.Unix(2, 0)),
+		GreaterOrEqualField("f", 1, "g", 2),
+		GreaterOrEqualField("f", time.Unix(1, 0), "g", time.Unix(2, 0)),
+	}
+	c := NewCatalog()
+
+	for _, err := range errs {
+		e := err.(*Error) //nolint:errorlint
+		t.Run(e.Msg, func(t *testing.T) {
+			got, ok := c.Translate("en", e)
+
+			assert.True(t, ok)
+			assert.Equal(t, e.Msg, got)
+		})
+	}
+}
+
+func TestCatalog_Translate(t *testing.T) {
+	c := NewCatalog()
+	c.Add("de", map[string]string{
+		CodeRequired:         "ist erforderlich",
+		CodeTooShort:         "muss mindestens {min} Zeichen lang sein",
+		CodeNotOneOf:         "muss einer der Werte {options} sein",
+		"invalid_format.url": "muss eine gültige URL sein",
+	})
+	c.Add("de_AT", map[string]string{
+		CodeRequired: "ist verpflichtend",
+	})
+
+	tests := []struct {
+		name   string
+		locale string
+		err    error
+		want   string
+		wantOk bool
+	}{
+		{
+			name:   "translated",
+			locale: "de",
+			err:    RequireField("f", ""),
+			want:   "ist erforderlich",
+			wantOk: true,
+		},
+		{
+			name:   "params",
+			locale: "de",
+			err:    MinLength("f", "", 1),
+			want:   "muss mindestens 1 Zeichen lang sein",
+			wantOk: true,
+		},
+		{
+			name:   "slice params",
+			locale: "de",
+			err:    OneOf("f", "c", "a", "b"),
+			want:   "muss einer der Werte a, b sein",
+			wantOk: true,
+		},
+		{
+			name:   "format",
+			locale: "de",
+			err:    URL("f", "", "https"),
+			want:   "muss eine gültige URL sein",
+			wantOk: true,
+		},
+		{
+			name:   "region",
+			locale: "de-AT",
+			err:    RequireField("f", ""),
+			want:   "ist verpflichtend",
+			wantOk: true,
+		},
+		{
+			name:   "region falls back to language",
+			locale: "de-at",
+			err:    MinLength("f", "", 2),
+			want:   "muss mindestens 2 Zeichen lang sein",
+			wantOk: true,
+		},
+		{
+			name:   "language falls back to fallback locale",
+			locale: "de",
+			err:    MaxLength("f", "ab", 1),
+			want:   "must be at most 1 character long",
+			wantOk: true,
+		},
+		{
+			name:   "unknown locale",
+			locale: "fr",
+			err:    RequireField("f", ""),
+			want:   "is required",
+			wantOk: true,
+		},
+		{
+			name:   "empty locale",
+			locale: "",
+			err:    RequireField("f", ""),
+			want:   "is required",
+			wantOk: true,
+		},
+		{
+			name:   "unknown code",
+			locale: "de",
+			err:    &Error{Code: "custom", Msg: "is custom"},
+			wantOk: false,
+		},
+		{
+			name:   "no code",
+			locale: "de",
+			err:    &Error{Msg: "is custom"},
+			wantOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := c.Translate(tt.locale, tt.err.(*Error))
+
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCatalog_SetFallback(t *testing.T) {
+	c := NewCatalog()
+	c.Add("de", map[string]string{CodeRequired: "ist erforderlich"})
+
+	c.SetFallback("de")
+	got, ok := c.Translate("fr", &Error{Code: CodeRequired})
+
+	assert.True(t, ok)
+	assert.Equal(t, "ist erforderlich", got)
+
+	c.SetFallback("")
+	_, ok = c.Translate("fr", &Error{Code: CodeRequired})
+
+	assert.False(t, ok)
+}
+
+func TestCatalog_LoadJSON(t *testing.T) {
+	c := NewCatalog()
+
+	err := c.LoadJSON("es", strings.NewReader(
+		`{"required": "es obligatorio", "too_long": "máximo {max}"}`,
+	))
+	require.NoError(t, err)
+
+	got, _ := c.Translate("es", &Error{Code: CodeRequired})
+	assert.Equal(t, "es obligatorio", got)
+
+	got, _ = c.Translate("es", &Error{
+		Code: CodeTooLong, Params: map[string]interface{}{"max": 5},
+	})
+	assert.Equal(t, "máximo 5", got)
+
+	err = c.LoadJSON("es", strings.NewReader(`["nope"]`))
+	assert.EqualError(t, err,
+		"validate: failed to load es catalog: json: cannot unmarshal "+
+			"array into Go value of type map[string]string",
+	)
+}
+
+func TestCatalog_LoadYAML(t *testing.T) {
+	c := NewCatalog()
+
+	err := c.LoadYAML("fr", strings.NewReader(
+		"required: est obligatoire\n"+
+			"too_short: \"doit contenir au moins {min} caractères\"\n",
+	))
+	require.NoError(t, err)
+
+	got, _ := c.Translate("fr", &Error{Code: CodeRequired})
+	assert.Equal(t, "est obligatoire", got)
+
+	err = c.LoadYAML("fr", strings.NewReader("- nope\n"))
+	assert.Error(t, err)
+}
+
+func TestCatalog_LoadFile(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"nl.json": `{"required": "is verplicht"}`,
+		"it.yml":  "required: è obbligatorio\n",
+		"pt.yaml": "required: é obrigatório\n",
+		"sv.txt":  "required: krävs\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0o600))
+	}
+
+	c := NewCatalog()
+
+	require.NoError(t, c.LoadFile("nl", filepath.Join(dir, "nl.json")))
+	require.NoError(t, c.LoadFile("it", filepath.Join(dir, "it.yml")))
+	require.NoError(t, c.LoadFile("pt", filepath.Join(dir, "pt.yaml")))
+
+	for locale, want := range map[string]string{
+		"nl":    "is verplicht",
+		"it":    "è obbligatorio",
+		"pt-BR": "é obrigatório",
+	} {
+		got, _ := c.Translate(locale, &Error{Code: CodeRequired})
+		assert.Equal(t, want, got)
+	}
+
+	err := c.LoadFile("sv", filepath.Join(dir, "sv.txt"))
+	assert.EqualError(t, err,
+		"validate: unsupported catalog file extension: "+
+			filepath.Join(dir, "sv.txt"),
+	)
+
+	err = c.LoadFile("sv", filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestValidator_ValidateContext_translator(t *testing.T) {
+	c := NewCatalog()
+	c.Add("de", map[string]string{
+		CodeRequired: "ist erforderlich",
+		CodeTooShort: "muss mindestens {min} Zeichen lang sein",
+	})
+	v := New(WithTranslator(c))
+	obj := &testNestedStruct{OtherField: &testStruct{
+		f: func() error {
+			errs := Append(nil, RequireField("Foo", ""))
+			errs = Append(errs, MinLength("Foo", "ab", 3))
+			errs = Append(errs, WithMessage(
+				RequireField("Foo", ""), "must be given",
+			))
+
+			return AppendFieldError(errs, "Foo", "is custom")
+		},
+	}}
+
+	err := v.ValidateContext(
+		ContextWithLocale(context.Background(), "de-DE"), obj,
+	)
+
+	var msgs []string
+	for _, e := range Errors(err) {
+		msgs = append(msgs, e.(*Error).Msg) //nolint:errorlint
+	}
+	assert.Equal(t, []string{
+		"ist erforderlich",
+		"muss mindestens 3 Zeichen lang sein",
+		"must be given",
+		"is custom",
+	}, msgs)
+
+	err = v.Validate(obj)
+
+	assert.Equal(t,
+		"other_field.foo: is required; "+
+			"other_field.foo: must be at least 3 characters long; "+
+			"other_field.foo: must be given; "+
+			"other_field.foo: is custom",
+		err.Error(),
+	)
+}
+
+func TestLocaleFromContext(t *testing.T) {
+	ctx := context.Background()
+
+	assert.Equal(t, "", LocaleFromContext(ctx))
+	assert.Equal(t, "de", LocaleFromContext(ContextWithLocale(ctx, "de")))
+}
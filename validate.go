@@ -17,6 +17,19 @@
 // can be fully validated, and the nested path to each object is tracked and
 // reported back any validation errors.
 //
+// Context
+//
+// Types which need access to a context.Context during validation can instead
+// implement the ValidatableWithContext interface:
+//
+//  type ValidatableWithContext interface {
+//      ValidateContext(ctx context.Context) error
+//  }
+//
+// The context passed to ValidateContext() is threaded through to all nested
+// objects. If the context is canceled, validation is aborted and the context's
+// error is returned.
+//
 // Multiple Errors
 //
 // Multiple errors can be reported from the Validate method using one of the
@@ -65,12 +78,144 @@
 //  Title: is required
 //  Kind: is required
 //
+// Field Helpers
+//
+// Common checks are available as helper functions, which return a *Error for
+// the given field if the check fails, and nil otherwise. Besides RequireField,
+// there are helpers for length bounds of strings, slices, and maps (MinLength,
+// MaxLength, LengthBetween), numeric bounds (Min, Max, Between), enums (OneOf),
+// strings (MatchRegexp, HasPrefix, HasSuffix), unique slice items
+// (UniqueItems), and durations (NonNegativeDuration). Their default messages
+// can be overridden with WithMessage:
+//
+//  func (s *Book) Validate() error {
+//      var errs error
+//      errs = validate.Append(errs, validate.RequireField("Title", s.Title))
+//      errs = validate.Append(errs, validate.WithMessage(
+//          validate.LengthBetween("Title", s.Title, 1, 200), "is too long",
+//      ))
+//      errs = validate.Append(errs, validate.UniqueItems("Tags", s.Tags))
+//
+//      return errs
+//  }
+//
+// Conditional presence checks avoid if-ladders in Validate methods. The
+// RequireFieldIf and ForbidFieldIf helpers only check a field if a condition
+// holds, while RequireFieldWith, RequireFieldWithAll, RequireFieldWithout,
+// RequireFieldWithoutAll, and ForbidFieldWith check a field depending on
+// whether other values are set:
+//
+//  errs = validate.Append(errs, validate.RequireFieldIf(
+//      "Reason", s.Reason, s.Status == "rejected",
+//  ))
+//  errs = validate.Append(errs, validate.RequireFieldWithoutAll(
+//      "Email", s.Email, s.Phone,
+//  ))
+//
+// Two fields of the same struct can be compared with EqualField,
+// NotEqualField, LessField, LessOrEqualField, GreaterField, and
+// GreaterOrEqualField, which support numbers, durations, and times. Errors
+// refer to the other field by its display name, as resolved by the
+// FieldNameFunc:
+//
+//  errs = validate.Append(errs, validate.LessField(
+//      "StartTime", s.StartTime, "EndTime", s.EndTime,
+//  ))
+//
+// Which for a StartTime field with a "start_time" json tag, and a EndTime
+// field with a "end_time" json tag, would yield the following error:
+//
+//  start_time: must be before end_time
+//
+// Helpers for common string formats are also available, like Email, URL,
+// Hostname, IPv4, IPv6, CIDR, UUID, RFC3339, ISO8601, HexColor, Base64, Semver,
+// and E164. Empty strings are never valid in any format, so optional fields
+// should only be checked when they are not empty.
+//
+// Nested Field Errors
+//
+// The Field of a *Error returned from a Validate method may also refer to a
+// value nested within the object, by joining Go field names, slice and array
+// indexes, and map keys with dots. For example, a Validate method on the Order
+// struct described under Nested Validatable Objects below could return a error
+// for "Items.1.Book.Author", which would be reported as "items.1.book.Author",
+// just like errors found while traversing nested objects.
+//
 // Error type
 //
 // All errors will be wrapped in a *Error before being returned, which is used
 // to keep track of the path and field the error relates to. There are various
 // helpers available to create Error instances.
 //
+// Besides the Field string, each *Error also has a Path, which holds the same
+// path as a list of PathSegment values. Each segment is either a struct field
+// with both its Go name and display name, a slice or array index, or a map key
+// with its original value.
+//
+// Errors created by RequireField, other helpers, and built-in tag rules also
+// have a machine-readable Code, like "required" or "too_short", and Params
+// holding the parameters of the failed constraint, like {"min": 3}. Unlike
+// Msg, codes are stable, so API clients can match on them. Custom errors can
+// set their own Code and Params, which are preserved when returned from
+// Validate methods.
+//
+// Errors can be encoded to and decoded from JSON, including their path, code,
+// params, and the message of any wrapped error. To pass all errors returned by
+// Validate across service boundaries, convert them with NewErrorList(), and use
+// the Err() method of a decoded ErrorList to get a single error again.
+//
+// Warnings
+//
+// Problems which should be flagged without failing validation, like the use of
+// deprecated settings, can be reported as warnings by setting the Severity of
+// a error to SeverityWarning or SeverityInfo. The AppendFieldWarning() and
+// WithSeverity() helpers make this easy:
+//
+//  func (s *Image) Validate() error {
+//      var errs error
+//      errs = validate.Append(errs, validate.RequireField("URI", s.URI))
+//      if s.Tag == "latest" {
+//          errs = validate.AppendFieldWarning(errs,
+//              "Tag", "latest is deprecated, use a specific version",
+//          )
+//      }
+//      return errs
+//  }
+//
+// Warnings are omitted from the error returned by Validate(). Use Check()
+// instead to get a Result holding both errors and warnings, which is valid as
+// long as there are no errors:
+//
+//  res := validate.Check(manifest)
+//  for _, w := range validate.Errors(res.Warnings) {
+//      fmt.Println("warning:", w)
+//  }
+//  if !res.Valid() {
+//      return res.Errors
+//  }
+//
+// Warnings do not count towards the maximum number of errors set with the
+// WithMaxErrors() option.
+//
+// Translating Error Messages
+//
+// Messages of errors with a Code can be translated by creating a custom
+// Validator instance with the WithTranslator() option. The bundled Catalog
+// translator includes English messages for all built-in codes, and additional
+// locales can be added from Go maps, or loaded from JSON or YAML files. The
+// locale is passed via the context given to ValidateContext():
+//
+//  catalog := validate.NewCatalog()
+//  err := catalog.LoadFile("de", "locales/de.json")
+//
+//  v := validate.New(validate.WithTranslator(catalog))
+//  ctx := validate.ContextWithLocale(r.Context(), "de-DE")
+//  err = v.ValidateContext(ctx, book)
+//
+// Message templates refer to params with placeholders like
+// "must be at least {min} characters long". See Catalog for details on how
+// templates are selected.
+//
 // Handling Validation Errors
 //
 // As mentioned above, multiple errors are wrapped up into a single error return
@@ -79,6 +224,128 @@
 // function is just wrapper around multierr.Errors(), so you could use that
 // instead if you prefer.
 //
+// Errors are always returned in a stable order. Struct fields are visited in
+// order of declaration, slice and array items by index, and map entries sorted
+// by key. Map key types can customize their order by implementing SortKeyer.
+//
+// HTTP services can render errors returned by Validate as a RFC 9457 Problem
+// Details document with NewProblem() or WriteProblem(), which list each error's
+// JSON Pointer, field, code, and message in a "errors" extension member:
+//
+//  if err := validate.Validate(book); err != nil {
+//      _ = validate.WriteProblem(w, err)
+//      return
+//  }
+//
+// The httpvalidate subpackage goes a step further, decoding JSON, form, and
+// query string requests before validating them, and responding with Problem
+// Details documents when either step fails.
+//
+// Struct Tag Rules
+//
+// Simple field constraints can be declared with a "validate" struct field tag
+// instead of a Validate method, once enabled by creating a custom Validator
+// instance with the WithTagRules() option:
+//
+//  type User struct {
+//      Name  string `json:"name" validate:"required,min=3,max=64"`
+//      Email string `json:"email" validate:"email"`
+//      Role  string `json:"role" validate:"oneof=admin user"`
+//  }
+//
+// Multiple rules are separated by commas, and parameters follow a equals sign,
+// with multiple parameters separated by spaces. All rules other than
+// "required" are skipped for empty/zero values. Errors from rules are reported
+// with the same path and field name resolution as errors from Validate
+// methods. The built-in rules are:
+//
+//  required  value must not be empty/zero
+//  empty     value must be empty/zero
+//  min=N     minimum length of strings/collections, or minimum number
+//  max=N     maximum length of strings/collections, or maximum number
+//  len=N     exact length of strings/collections
+//  oneof=A B value must be one of the given values
+//  email     value must be a email address
+//  url=S...  value must be a URL, optionally with one of the given schemes
+//  hostname  value must be a RFC 1123 hostname
+//  ipv4      value must be a IPv4 address
+//  ipv6      value must be a IPv6 address
+//  cidr      value must be a IP address and prefix length in CIDR notation
+//  uuid=V    value must be a UUID, optionally of the given version
+//  rfc3339   value must be a RFC 3339 timestamp
+//  iso8601   value must be a ISO 8601 date or timestamp
+//  hexcolor  value must be a hex color code, like "#ff0000"
+//  base64    value must be standard base64 encoded data
+//  semver    value must be a semantic version
+//  e164      value must be a E.164 phone number
+//
+// Custom rules can be registered by name with RegisterRule() on a Validator,
+// or with the WithRule() option. Custom rules receive the field's value, the
+// rule's parameters, and the struct containing the field:
+//
+//  v := validate.New(
+//      validate.WithTagRules(),
+//      validate.WithRule("sku", func(
+//          value reflect.Value, params []string, parent reflect.Value,
+//      ) error {
+//          if !skuRegexp.MatchString(value.String()) {
+//              return &validate.Error{Msg: "must be a valid SKU"}
+//          }
+//          return nil
+//      }),
+//  )
+//
+// Scenarios
+//
+// The same type often needs different checks depending on the operation, like
+// a ID which must be empty when creating a object, but present when updating
+// it. Such operations can be named as scenarios, which are passed to
+// ValidateContext() with ContextWithScenario():
+//
+//  ctx := validate.ContextWithScenario(r.Context(), "update")
+//  err := v.ValidateContext(ctx, user)
+//
+// The scenario is available to all nested ValidatableWithContext objects via
+// ScenarioFromContext():
+//
+//  func (s *User) ValidateContext(ctx context.Context) error {
+//      switch validate.ScenarioFromContext(ctx) {
+//      case "create":
+//          return validate.ForbidField("ID", s.ID)
+//      case "update":
+//          return validate.RequireField("ID", s.ID)
+//      }
+//      return nil
+//  }
+//
+// Tag rules can be scoped to scenarios with "on=", which limits all following
+// rules in the tag to the listed scenarios. Rules before any "on=" apply to all
+// scenarios, while scoped rules are skipped when no scenario is set:
+//
+//  type User struct {
+//      ID   string `json:"id" validate:"on=create,empty,on=update,required"`
+//      Name string `json:"name" validate:"max=64,on=create,required"`
+//  }
+//
+// Partial Validation
+//
+// Partial updates, like PATCH requests, should only be validated for the
+// fields the client actually sent, rather than failing with "is required" for
+// every omitted field. A FieldMask listing the paths to validate can be passed
+// to ValidateContext() with ContextWithFieldMask(). Only values covered by the
+// mask are walked, and only errors for covered paths are reported:
+//
+//  mask, err := validate.FieldMaskFromJSON(body)
+//  if err != nil {
+//      return err
+//  }
+//  ctx := validate.ContextWithFieldMask(r.Context(), mask)
+//  err = v.ValidateContext(ctx, &patched)
+//
+// Masks can also be created from a list of paths with NewFieldMask(), like
+// NewFieldMask("name", "address.city"), where paths use the same display names
+// as the Field of errors.
+//
 // Struct Field Tags
 //
 // Fields on a struct which customize the name via a json, yaml, or form field
@@ -86,7 +353,7 @@
 // in returned *Error types with a non-empty Field value.
 //
 // You can customize the field name conversion logic by creating a custom
-// Validator instance, and calling FieldNameFunc() on it.
+// Validator instance with the WithFieldNameFunc() option.
 //
 // Nested Validatable Objects
 //
@@ -96,9 +363,31 @@
 // current object being validation in relation to the top-level object being
 // validated. This path is used within the field in the final output errors.
 //
+// Validate methods with pointer receivers are called for struct fields, slice
+// items, and array items which are not pointers, as they are addressable. Map
+// values and values stored in interfaces are not addressable however, so only
+// Validate methods with value receivers can be called on them. Such values can
+// be reported as errors wrapping ErrNotAddressable by creating a custom
+// Validator instance with the WithStrict() option.
+//
+// Types are analyzed to determine if they can contain any Validatable values.
+// Those which cannot, like []byte or map[string]string, are skipped entirely
+// without iterating over their items.
+//
+// Self-referencing data structures are supported. Any pointer, map, or slice
+// which is encountered again while it is still being validated further up the
+// current path is skipped, preventing infinite recursion. Reference cycles can
+// optionally be reported as errors wrapping ErrCycle by creating a custom
+// Validator instance with the WithReportCycles() option.
+//
 // By default path components are joined with a dot, but this can be customized
-// when using a custom Validator instance and calling FieldJoinFunc() passing in
-// a custom function to handle path joining.
+// when using a custom Validator instance created with the WithFieldJoinFunc()
+// option, passing in a custom function to handle path joining.
+//
+// Alternatively the WithPathFormatFunc() option can be used to render the full
+// structured path of each error. The JSONPointer and JSONPath functions render
+// paths as RFC 6901 JSON Pointers and JSONPath bracket notation respectively,
+// neither of which are ambiguous when map keys contain dots.
 //
 // As an example, if our Book struct from above is nested within the following
 // structs:
@@ -137,8 +426,11 @@
 // part of the returned field path.
 package validate
 
+import "context"
+
 // global is a private instance of Validator to enable the package root-level
-// Validate() function.
+// Validate() function. As it is never reconfigured, it is safe for concurrent
+// use.
 var global = New()
 
 // Validate will validate the given object. Structs, maps, slices, and arrays
@@ -148,6 +440,24 @@ func Validate(v interface{}) error {
 	return global.Validate(v)
 }
 
+// ValidateContext will validate the given object in the same way as Validate,
+// passing ctx to all ValidatableWithContext objects encountered.
+func ValidateContext(ctx context.Context, v interface{}) error {
+	return global.ValidateContext(ctx, v)
+}
+
+// Check will validate the given object in the same way as Validate, returning
+// a Result which holds both errors and warnings.
+func Check(v interface{}) *Result {
+	return global.Check(v)
+}
+
+// CheckContext will validate the given object in the same way as Check,
+// passing ctx to all ValidatableWithContext objects encountered.
+func CheckContext(ctx context.Context, v interface{}) *Result {
+	return global.CheckContext(ctx, v)
+}
+
 // Validatable is the primary interface that a object needs to implement to be
 // validatable with Validator.
 //
@@ -160,3 +470,13 @@ func Validate(v interface{}) error {
 type Validatable interface {
 	Validate() error
 }
+
+// ValidatableWithContext is an alternative to Validatable, for objects which
+// need access to a context.Context during validation, for example to access
+// request-scoped values or to perform lookups which honor cancellation.
+//
+// Objects implementing both ValidatableWithContext and Validatable will only
+// have their ValidateContext method called.
+type ValidatableWithContext interface {
+	ValidateContext(ctx context.Context) error
+}
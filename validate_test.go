@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/romdo/go-validate"
@@ -101,6 +102,22 @@ type nestedStruct struct {
 	OtherStructFORM *nestedStruct `form:"other-struct,omitempty"`
 }
 
+// withoutPaths returns copies of all *validate.Error values in errs with Path
+// unset, allowing tests to focus on the resulting Field values.
+func withoutPaths(errs []error) []error {
+	r := make([]error, 0, len(errs))
+	for _, err := range errs {
+		if e, ok := err.(*validate.Error); ok { //nolint:errorlint
+			c := *e
+			c.Path = nil
+			err = &c
+		}
+		r = append(r, err)
+	}
+
+	return r
+}
+
 //
 // Tests
 //
@@ -949,8 +966,34 @@ func TestValidate(t *testing.T) {
 				assert.Nil(t, err, "validation error should be nil")
 			}
 
-			got := validate.Errors(err)
+			got := withoutPaths(validate.Errors(err))
 			assert.ElementsMatch(t, tt.wantErrs, got)
 		})
 	}
 }
+
+func TestValidate_concurrent(t *testing.T) {
+	obj := &nestedStruct{
+		OtherFieldJSON: &validatableStruct{f: func() error {
+			return &validate.Error{Field: "Bar", Msg: "is required"}
+		}},
+	}
+	want := []error{
+		&validate.Error{Field: "other_field.bar", Msg: "is required"},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				err := validate.Validate(obj)
+				assert.ElementsMatch(
+					t, want, withoutPaths(validate.Errors(err)),
+				)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -1,11 +1,11 @@
 package validate
 
 import (
+	"context"
 	"errors"
-	"fmt"
 	"reflect"
-	"strconv"
 	"strings"
+	"sync"
 
 	"go.uber.org/multierr"
 )
@@ -19,111 +19,456 @@ type FieldNameFunc func(reflect.StructField) string
 type FieldJoinFunc func(path []string, field string) string
 
 // Validator validates Validatable objects.
+//
+// A Validator is safe for concurrent use by multiple goroutines, as long as
+// it is not reconfigured while in use. Configuration should be provided via
+// Options passed to New.
 type Validator struct {
-	fieldName FieldNameFunc
-	fieldJoin FieldJoinFunc
+	fieldName    FieldNameFunc
+	fieldJoin    FieldJoinFunc
+	pathFormat   PathFormatFunc
+	reportCycles bool
+	maxErrors    int
+	strict       bool
+	tagRules     bool
+	rules        map[string]RuleFunc
+	paramChecks  map[string]paramCheck
+	rulesGen     uint64
+	rulesMu      sync.RWMutex
+	translator   Translator
+
+	types *typeCache
 }
 
-// New creates a new Validator.
-func New() *Validator {
-	return &Validator{}
+// New creates a new Validator configured with the given options.
+func New(opts ...Option) *Validator {
+	s := &Validator{
+		fieldName:   DefaultFieldName,
+		fieldJoin:   DefaultFieldJoin,
+		types:       &typeCache{},
+		rules:       map[string]RuleFunc{},
+		paramChecks: map[string]paramCheck{},
+	}
+
+	for name, fn := range builtinRules {
+		s.rules[name] = fn
+	}
+
+	for name, check := range builtinParamChecks {
+		s.paramChecks[name] = check
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
 
 // Validate will validate the given object. Structs, maps, slices, and arrays
 // will have each of their fields/items validated, effectively performing a
 // deep-validation.
+//
+// Errors are returned in a stable order. For each object, errors returned by
+// its own Validate method are reported first in the order they were returned,
+// followed by errors from nested objects. Struct fields are visited in order of
+// declaration, slice and array items by index, and map entries sorted by key.
 func (s *Validator) Validate(data interface{}) error {
-	if s.fieldName == nil {
-		s.fieldName = DefaultFieldName
+	return s.ValidateContext(context.Background(), data)
+}
+
+// ValidateContext will validate the given object in the same way as Validate,
+// passing ctx to all ValidatableWithContext objects encountered.
+//
+// If ctx is canceled or its deadline is exceeded before validation completes,
+// validation is aborted and ctx.Err() is returned instead of any validation
+// errors.
+func (s *Validator) ValidateContext(
+	ctx context.Context,
+	data interface{},
+) error {
+	return s.CheckContext(ctx, data).Errors
+}
+
+// Check validates the given object in the same way as Validate, but returns a
+// Result which holds both the errors which cause validation to fail, and any
+// warnings reported with SeverityWarning or SeverityInfo.
+func (s *Validator) Check(data interface{}) *Result {
+	return s.CheckContext(context.Background(), data)
+}
+
+// CheckContext validates the given object in the same way as Check, passing
+// ctx to all ValidatableWithContext objects encountered.
+//
+// If ctx is canceled or its deadline is exceeded before validation completes,
+// the Result's Errors is set to ctx.Err(), and any warnings are discarded.
+func (s *Validator) CheckContext(
+	ctx context.Context,
+	data interface{},
+) *Result {
+	w := &walker{
+		Validator: s,
+		ctx:       ctx,
+		locale:    LocaleFromContext(ctx),
+		scenario:  ScenarioFromContext(ctx),
+		mask:      FieldMaskFromContext(ctx),
+		visited:   map[visitKey]bool{},
 	}
 
-	if s.fieldJoin == nil {
-		s.fieldJoin = DefaultFieldJoin
+	w.validate(nil, data)
+	if err := ctx.Err(); err != nil {
+		return &Result{Errors: err}
 	}
 
-	return s.validate(nil, data)
+	return &Result{Errors: w.errs, Warnings: w.warns}
 }
 
 // FieldNameFunc allows setting a custom FieldNameFunc method. It receives a
 // reflect.StructField, and must return a string for the name of that field. If
 // the returned string is empty, validation will not run against the field's
 // value, or any nested data within.
+//
+// Deprecated: Use the WithFieldNameFunc option with New instead, as calling
+// FieldNameFunc is not safe while the Validator is in use.
 func (s *Validator) FieldNameFunc(f FieldNameFunc) {
+	if f == nil {
+		f = DefaultFieldName
+	}
 	s.fieldName = f
+	s.types = &typeCache{}
 }
 
 // FieldJoinFunc allows setting a custom FieldJoinFunc method. It receives a
 // string slice of parent fields, and a string of the field name the error is
 // reported against. All parent paths, must be joined with the current.
+//
+// Deprecated: Use the WithFieldJoinFunc option with New instead, as calling
+// FieldJoinFunc is not safe while the Validator is in use.
 func (s *Validator) FieldJoinFunc(f FieldJoinFunc) {
+	if f == nil {
+		f = DefaultFieldJoin
+	}
 	s.fieldJoin = f
 }
 
-func (s *Validator) validate(path []string, data interface{}) error {
-	var errs error
+// visitKey identifies a pointer, map, or slice encountered while walking an
+// object. The type is included as a struct and its first field share the same
+// address, and the length as slices of the same array may differ in length.
+type visitKey struct {
+	ptr uintptr
+	typ reflect.Type
+	len int
+}
+
+// walker holds the state of a single validation run.
+type walker struct {
+	*Validator
+	ctx      context.Context
+	locale   string
+	scenario string
+
+	// mask limits the values walked and errors reported, if set.
+	mask *FieldMask
+
+	// visited tracks pointers, maps, and slices currently being validated
+	// higher up in the current path, to detect reference cycles.
+	visited map[visitKey]bool
+
+	errs  error
+	count int
+
+	// warns holds reported errors with a severity other than SeverityError,
+	// which do not count towards the maximum number of errors.
+	warns error
+}
+
+// report appends the given error to the errors or warnings found so far,
+// unless the maximum number of errors has already been reached, or the error's
+// path is not covered by the FieldMask. The error's message is translated if a
+// Translator is configured, unless it was customized, like with WithMessage.
+func (s *walker) report(err *Error) {
+	if s.done() {
+		return
+	}
+
+	if s.mask != nil && !s.mask.covers(pathStrings(err.Path)) {
+		return
+	}
+
+	if s.translator != nil && err.Code != "" && hasDefaultMessage(err) {
+		if msg, ok := s.translator.Translate(s.locale, err); ok {
+			err.Msg = msg
+		}
+	}
+
+	if err.Severity != SeverityError {
+		s.warns = multierr.Append(s.warns, err)
+
+		return
+	}
+
+	s.errs = multierr.Append(s.errs, err)
+	s.count++
+}
+
+// hasDefaultMessage checks if the error's Msg is empty, or the English message
+// for its Code and Params, meaning it has not been customized.
+func hasDefaultMessage(err *Error) bool {
+	if err.Msg == "" {
+		return true
+	}
+
+	msg, ok := englishMessage(err)
+
+	return ok && err.Msg == msg
+}
+
+// done reports if validation should stop, either due to the context being
+// done, or the maximum number of errors having been reached.
+func (s *walker) done() bool {
+	return s.ctx.Err() != nil ||
+		(s.maxErrors > 0 && s.count >= s.maxErrors)
+}
+
+// validate validates the given top-level object. Non-pointer values are copied
+// to make them addressable, so Validate methods with pointer receivers can be
+// called.
+func (s *walker) validate(path []PathSegment, data interface{}) {
 	if data == nil {
-		return nil
+		return
 	}
+
 	d := reflect.ValueOf(data)
-	if d.Kind() == reflect.Ptr {
-		if d.IsNil() {
-			return nil
-		}
+	if d.Kind() != reflect.Ptr {
+		c := reflect.New(d.Type()).Elem()
+		c.Set(d)
+		d = c
+	}
+
+	s.walk(path, d)
+}
+
+// walk validates the given value, and all values nested within it.
+func (s *walker) walk(path []PathSegment, d reflect.Value) {
+	for d.Kind() == reflect.Interface {
 		d = d.Elem()
 	}
 
-	if v, ok := data.(Validatable); ok {
-		verrs := v.Validate()
-		for _, err := range multierr.Errors(verrs) {
-			// Create a new Error for all errors returned by Validate function
-			// to correctly resolve field name, and also field path in relation
-			// to parent objects being validated.
-			newErr := &Error{}
-
-			e := &Error{}
-			if ok := errors.As(err, &e); ok {
-				field := e.Field
-				if field != "" && d.Kind() == reflect.Struct {
-					if sf, ok := d.Type().FieldByName(e.Field); ok {
-						field = s.fieldName(sf)
-					}
-				}
-				newErr.Field = s.fieldJoin(path, field)
-				newErr.Msg = e.Msg
-				newErr.Err = e.Err
-			} else {
-				newErr.Field = s.fieldJoin(path, "")
-				newErr.Err = err
+	if !d.IsValid() || s.done() || !s.typeInfo(d.Type()).walk {
+		return
+	}
+
+	if s.mask != nil && !s.mask.includes(pathStrings(path)) {
+		return
+	}
+
+	switch d.Kind() { //nolint:exhaustive
+	case reflect.Ptr, reflect.Map, reflect.Slice:
+		if d.IsNil() {
+			return
+		}
+
+		key := visitKey{ptr: d.Pointer(), typ: d.Type()}
+		if d.Kind() == reflect.Slice {
+			key.len = d.Len()
+		}
+
+		if s.visited[key] {
+			if s.reportCycles {
+				s.report(s.newError(path, ErrCycle))
 			}
 
-			errs = multierr.Append(errs, newErr)
+			return
 		}
+
+		s.visited[key] = true
+		defer delete(s.visited, key)
+	}
+
+	if d.Kind() == reflect.Ptr {
+		elem := d.Elem()
+		s.call(path, d, elem)
+
+		if elem.Kind() == reflect.Ptr || elem.Kind() == reflect.Interface {
+			s.walk(path, elem)
+		} else {
+			s.walkChildren(path, elem)
+		}
+
+		return
 	}
 
+	if d.CanAddr() {
+		s.call(path, d.Addr(), d)
+	} else if !s.call(path, d, d) && s.strict &&
+		implementsValidatable(d.Type()) {
+		s.report(s.newError(path, ErrNotAddressable))
+	}
+
+	s.walkChildren(path, d)
+}
+
+// walkChildren validates all items/fields of the given value.
+func (s *walker) walkChildren(path []PathSegment, d reflect.Value) {
 	switch d.Kind() { //nolint:exhaustive
 	case reflect.Slice, reflect.Array:
-		for i := 0; i < d.Len(); i++ {
-			v := d.Index(i)
-			err := s.validate(append(path, strconv.Itoa(i)), v.Interface())
-			errs = multierr.Append(errs, err)
+		for i := 0; i < d.Len() && !s.done(); i++ {
+			seg := PathSegment{Kind: IndexSegment, Index: i}
+			s.walk(append(path, seg), d.Index(i))
 		}
 	case reflect.Map:
-		for _, k := range d.MapKeys() {
-			v := d.MapIndex(k)
-			err := s.validate(append(path, fmt.Sprintf("%v", k)), v.Interface())
-			errs = multierr.Append(errs, err)
+		for _, k := range sortedMapKeys(d) {
+			if s.done() {
+				break
+			}
+			seg := PathSegment{Kind: KeySegment, Key: k.Interface()}
+			s.walk(append(path, seg), d.MapIndex(k))
 		}
 	case reflect.Struct:
-		for i := 0; i < d.NumField(); i++ {
-			v := d.Field(i)
-			fldName := s.fieldName(d.Type().Field(i))
-			if v.CanSet() && fldName != "" {
-				err := s.validate(append(path, fldName), v.Interface())
-				errs = multierr.Append(errs, err)
+		for _, f := range s.typeInfo(d.Type()).fields {
+			if s.done() {
+				break
+			}
+			seg := PathSegment{
+				Kind: FieldSegment, Name: f.name, Field: f.field,
+			}
+			if len(f.rules) > 0 {
+				s.applyRules(append(path, seg), f.rules, d.Field(f.index), d)
+			}
+			if f.walk {
+				s.walk(append(path, seg), d.Field(f.index))
 			}
 		}
 	}
-	return errs
+}
+
+// call calls the Validate or ValidateContext method of v if available, and
+// reports all returned errors. The value d is the value v refers to, which is
+// used to resolve field names. It returns false if v has no Validate or
+// ValidateContext method.
+func (s *walker) call(
+	path []PathSegment,
+	v reflect.Value,
+	d reflect.Value,
+) bool {
+	var verrs error
+	switch o := v.Interface().(type) {
+	case ValidatableWithContext:
+		verrs = o.ValidateContext(s.ctx)
+	case Validatable:
+		verrs = o.Validate()
+	default:
+		return false
+	}
+
+	for _, err := range multierr.Errors(verrs) {
+		// Create a new Error for all errors returned by Validate function
+		// to correctly resolve field name, and also field path in relation
+		// to parent objects being validated.
+		e := &Error{}
+		if ok := errors.As(err, &e); !ok {
+			s.report(s.newError(path, err))
+
+			continue
+		}
+
+		newErr := &Error{
+			Code:     e.Code,
+			Params:   e.Params,
+			Severity: e.Severity,
+			Msg:      e.Msg,
+			Err:      e.Err,
+		}
+		switch {
+		case len(e.Path) > 0:
+			newErr.Path = joinPath(path, e.Path...)
+			newErr.Field = s.formatField(newErr.Path, nil)
+		case e.Field != "":
+			segs := s.resolveField(d.Type(), e.Field)
+			if n := len(segs); n > 0 {
+				newErr.Path = joinPath(path, segs...)
+				newErr.Field = s.formatField(
+					joinPath(path, segs[:n-1]...), &segs[n-1],
+				)
+			} else {
+				newErr.Path = joinPath(path)
+				newErr.Field = s.formatField(path, nil)
+			}
+		default:
+			newErr.Path = joinPath(path)
+			newErr.Field = s.formatField(path, nil)
+		}
+
+		s.resolveParams(d.Type(), newErr)
+		s.report(newErr)
+	}
+
+	return true
+}
+
+// resolveParams replaces FieldRef params of err with the display names of the
+// fields they refer to, relative to values of type t. If the error's message
+// is the default English message, it is rendered again with resolved params.
+func (s *Validator) resolveParams(t reflect.Type, err *Error) {
+	var params map[string]interface{}
+	for k, v := range err.Params {
+		ref, ok := v.(FieldRef)
+		if !ok {
+			continue
+		}
+
+		if params == nil {
+			params = make(map[string]interface{}, len(err.Params))
+			for pk, pv := range err.Params {
+				params[pk] = pv
+			}
+		}
+
+		name := string(ref)
+		if segs := s.resolveField(t, name); len(segs) > 0 {
+			name = s.fieldJoin(pathStrings(segs), "")
+		}
+		params[k] = name
+	}
+
+	if params == nil {
+		return
+	}
+
+	msg, ok := englishMessage(err)
+	err.Params = params
+	if ok && msg == err.Msg {
+		err.Msg, _ = englishMessage(err)
+	}
+}
+
+// newError returns a new *Error wrapping err for the given path.
+func (s *walker) newError(path []PathSegment, err error) *Error {
+	return &Error{
+		Field: s.formatField(path, nil),
+		Path:  joinPath(path),
+		Err:   err,
+	}
+}
+
+// formatField renders the Field value of errors for the given path, with seg
+// as an optional final segment. A custom PathFormatFunc is used if available,
+// otherwise the path is joined with the FieldJoinFunc.
+func (s *Validator) formatField(path []PathSegment, seg *PathSegment) string {
+	if s.pathFormat != nil {
+		if seg != nil {
+			path = joinPath(path, *seg)
+		}
+
+		return s.pathFormat(path)
+	}
+
+	field := ""
+	if seg != nil {
+		field = seg.String()
+	}
+
+	return s.fieldJoin(pathStrings(path), field)
 }
 
 // DefaultFieldName is the default FieldNameFunc used by Validator.
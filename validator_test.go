@@ -1,9 +1,12 @@
 package validate
 
 import (
+	"context"
+	"errors"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -35,6 +38,22 @@ type MyStruct struct {
 	Kind string
 }
 
+// withoutPaths returns copies of all *Error values in errs with Path unset,
+// allowing tests to focus on the resulting Field values.
+func withoutPaths(errs []error) []error {
+	r := make([]error, 0, len(errs))
+	for _, err := range errs {
+		if e, ok := err.(*Error); ok { //nolint:errorlint
+			c := *e
+			c.Path = nil
+			err = &c
+		}
+		r = append(r, err)
+	}
+
+	return r
+}
+
 //
 // Tests
 //
@@ -45,6 +64,47 @@ func TestNew(t *testing.T) {
 	assert.IsType(t, &Validator{}, got)
 }
 
+func TestNew_options(t *testing.T) {
+	v := New(
+		WithFieldNameFunc(func(sf reflect.StructField) string {
+			return "<" + strings.ToUpper(sf.Name) + ">"
+		}),
+		WithFieldJoinFunc(func(path []string, field string) string {
+			if field != "" {
+				path = append(path, field)
+			}
+
+			return "[" + strings.Join(path, "][") + "]"
+		}),
+	)
+	err := v.Validate(&testNestedStruct{
+		OtherField: &testStruct{f: func() error {
+			return &Error{Field: "Foo", Msg: "oops"}
+		}},
+	})
+
+	got := withoutPaths(Errors(err))
+
+	assert.ElementsMatch(t, []error{
+		&Error{Field: "[<OTHERFIELD>][<FOO>]", Msg: "oops"},
+	}, got)
+}
+
+func TestNew_nilOptions(t *testing.T) {
+	v := New(WithFieldNameFunc(nil), WithFieldJoinFunc(nil))
+	err := v.Validate(&testNestedStruct{
+		OtherField: &testStruct{f: func() error {
+			return &Error{Field: "Foo", Msg: "oops"}
+		}},
+	})
+
+	got := withoutPaths(Errors(err))
+
+	assert.ElementsMatch(t, []error{
+		&Error{Field: "other_field.foo", Msg: "oops"},
+	}, got)
+}
+
 func TestValidator_FieldNameFunc(t *testing.T) {
 	v := New()
 	v.FieldNameFunc(func(sf reflect.StructField) string {
@@ -56,7 +116,7 @@ func TestValidator_FieldNameFunc(t *testing.T) {
 		}},
 	})
 
-	got := Errors(err)
+	got := withoutPaths(Errors(err))
 
 	assert.ElementsMatch(t, []error{
 		&Error{Field: "<OTHERFIELD>.<FOO>", Msg: "oops"},
@@ -78,9 +138,555 @@ func TestValidator_FieldJoinFunc(t *testing.T) {
 		}},
 	})
 
-	got := Errors(err)
+	got := withoutPaths(Errors(err))
 
 	assert.ElementsMatch(t, []error{
 		&Error{Field: "[other_field][foo]", Msg: "oops"},
 	}, got)
 }
+
+type testContextKey struct{}
+
+type testContextStruct struct {
+	Foo string `json:"foo"`
+
+	Nested *testContextStruct `json:"nested"`
+}
+
+func (s *testContextStruct) ValidateContext(ctx context.Context) error {
+	if want, _ := ctx.Value(testContextKey{}).(string); s.Foo != want {
+		return &Error{Field: "Foo", Msg: "must be " + want}
+	}
+
+	return nil
+}
+
+func (s *testContextStruct) Validate() error {
+	return &Error{Field: "Foo", Msg: "Validate should not be called"}
+}
+
+func TestValidator_ValidateContext(t *testing.T) {
+	ctx := context.WithValue(context.Background(), testContextKey{}, "bar")
+	err := New().ValidateContext(ctx, &testContextStruct{
+		Foo:    "bar",
+		Nested: &testContextStruct{Foo: "baz"},
+	})
+
+	got := withoutPaths(Errors(err))
+
+	assert.ElementsMatch(t, []error{
+		&Error{Field: "nested.foo", Msg: "must be bar"},
+	}, got)
+}
+
+func TestValidator_ValidateContext_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := New().ValidateContext(ctx, &testContextStruct{Foo: "baz"})
+
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Len(t, Errors(err), 1)
+}
+
+type testNode struct {
+	Name     string      `json:"name"`
+	Parent   *testNode   `json:"parent"`
+	Children []*testNode `json:"children"`
+	Next     *testNode   `json:"next"`
+	Prev     *testNode   `json:"prev"`
+}
+
+func (s *testNode) Validate() error {
+	return RequireField("Name", s.Name)
+}
+
+func TestValidator_Validate_cycles(t *testing.T) {
+	root := &testNode{}
+	first := &testNode{Name: "first", Parent: root}
+	second := &testNode{Parent: root, Prev: first}
+	first.Next = second
+	root.Children = []*testNode{first, second}
+
+	selfMap := map[string]interface{}{}
+	selfMap["self"] = selfMap
+	selfMap["node"] = &testNode{}
+
+	tests := []struct {
+		name         string
+		obj          interface{}
+		reportCycles bool
+		wantErrs     []error
+	}{
+		{
+			name: "doubly-linked list and parent references",
+			obj:  root,
+			wantErrs: []error{
+				&Error{Field: "name", Code: CodeRequired, Msg: "is required"},
+				&Error{
+					Field: "children.0.next.name",
+					Code:  CodeRequired,
+					Msg:   "is required",
+				},
+				&Error{
+					Field: "children.1.name",
+					Code:  CodeRequired,
+					Msg:   "is required",
+				},
+			},
+		},
+		{
+			name: "self-referencing map",
+			obj:  selfMap,
+			wantErrs: []error{
+				&Error{
+					Field: "node.name",
+					Code:  CodeRequired,
+					Msg:   "is required",
+				},
+			},
+		},
+		{
+			name:         "doubly-linked list with cycle reporting",
+			obj:          root,
+			reportCycles: true,
+			wantErrs: []error{
+				&Error{Field: "name", Code: CodeRequired, Msg: "is required"},
+				&Error{Field: "children.0.parent", Err: ErrCycle},
+				&Error{
+					Field: "children.0.next.name",
+					Code:  CodeRequired,
+					Msg:   "is required",
+				},
+				&Error{Field: "children.0.next.parent", Err: ErrCycle},
+				&Error{Field: "children.0.next.prev", Err: ErrCycle},
+				&Error{
+					Field: "children.1.name",
+					Code:  CodeRequired,
+					Msg:   "is required",
+				},
+				&Error{Field: "children.1.parent", Err: ErrCycle},
+				&Error{Field: "children.1.prev.parent", Err: ErrCycle},
+				&Error{Field: "children.1.prev.next", Err: ErrCycle},
+			},
+		},
+		{
+			name:         "self-referencing map with cycle reporting",
+			obj:          selfMap,
+			reportCycles: true,
+			wantErrs: []error{
+				&Error{Field: "self", Err: ErrCycle},
+				&Error{
+					Field: "node.name",
+					Code:  CodeRequired,
+					Msg:   "is required",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts []Option
+			if tt.reportCycles {
+				opts = append(opts, WithReportCycles())
+			}
+			v := New(opts...)
+
+			err := v.Validate(tt.obj)
+
+			assert.ElementsMatch(t, tt.wantErrs, withoutPaths(Errors(err)))
+		})
+	}
+}
+
+type testCountingStruct struct {
+	calls *int
+	errs  int
+}
+
+func (s *testCountingStruct) Validate() error {
+	*s.calls++
+
+	var errs error
+	for i := 0; i < s.errs; i++ {
+		errs = AppendError(errs, "is invalid")
+	}
+
+	return errs
+}
+
+func TestValidator_Validate_maxErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []Option
+		items     int
+		errs      int
+		wantErrs  int
+		wantCalls int
+	}{
+		{
+			name:      "no limit",
+			items:     100,
+			errs:      2,
+			wantErrs:  200,
+			wantCalls: 100,
+		},
+		{
+			name:      "fail fast",
+			opts:      []Option{WithFailFast()},
+			items:     100,
+			errs:      2,
+			wantErrs:  1,
+			wantCalls: 1,
+		},
+		{
+			name:      "max errors",
+			opts:      []Option{WithMaxErrors(50)},
+			items:     100,
+			errs:      2,
+			wantErrs:  50,
+			wantCalls: 25,
+		},
+		{
+			name:      "max errors reached mid-way through item",
+			opts:      []Option{WithMaxErrors(5)},
+			items:     100,
+			errs:      2,
+			wantErrs:  5,
+			wantCalls: 3,
+		},
+		{
+			name:      "max errors not reached",
+			opts:      []Option{WithMaxErrors(50)},
+			items:     10,
+			errs:      1,
+			wantErrs:  10,
+			wantCalls: 10,
+		},
+		{
+			name:      "negative max errors",
+			opts:      []Option{WithMaxErrors(-1)},
+			items:     10,
+			errs:      1,
+			wantErrs:  10,
+			wantCalls: 10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			items := make([]*testCountingStruct, tt.items)
+			for i := range items {
+				items[i] = &testCountingStruct{calls: &calls, errs: tt.errs}
+			}
+
+			err := New(tt.opts...).Validate(items)
+
+			assert.Len(t, Errors(err), tt.wantErrs)
+			assert.Equal(t, tt.wantCalls, calls)
+		})
+	}
+}
+
+type testValueFields struct {
+	Item     testStruct              `json:"item"`
+	Slice    []testStruct            `json:"slice"`
+	Array    [2]testStruct           `json:"array"`
+	Map      map[string]testStruct   `json:"map"`
+	Iface    interface{}             `json:"iface"`
+	PtrMap   map[string]*testStruct  `json:"ptr_map"`
+	ValueMap map[string]testValueRcv `json:"value_map"`
+}
+
+type testValueRcv struct {
+	Foo string `json:"foo"`
+}
+
+func (s testValueRcv) Validate() error {
+	return RequireField("Foo", s.Foo)
+}
+
+func TestValidator_Validate_pointerReceivers(t *testing.T) {
+	failing := func() error {
+		return &Error{Field: "Foo", Msg: "oops"}
+	}
+	obj := testValueFields{
+		Item:     testStruct{f: failing},
+		Slice:    []testStruct{{}, {f: failing}},
+		Array:    [2]testStruct{{f: failing}},
+		Map:      map[string]testStruct{"a": {f: failing}},
+		Iface:    testStruct{f: failing},
+		PtrMap:   map[string]*testStruct{"a": {f: failing}},
+		ValueMap: map[string]testValueRcv{"a": {}},
+	}
+
+	tests := []struct {
+		name     string
+		opts     []Option
+		obj      interface{}
+		wantErrs []error
+	}{
+		{
+			name: "pointer",
+			obj:  &obj,
+			wantErrs: []error{
+				&Error{Field: "item.foo", Msg: "oops"},
+				&Error{Field: "slice.1.foo", Msg: "oops"},
+				&Error{Field: "array.0.foo", Msg: "oops"},
+				&Error{Field: "ptr_map.a.foo", Msg: "oops"},
+				&Error{
+					Field: "value_map.a.foo",
+					Code:  CodeRequired,
+					Msg:   "is required",
+				},
+			},
+		},
+		{
+			name: "value",
+			obj:  obj,
+			wantErrs: []error{
+				&Error{Field: "item.foo", Msg: "oops"},
+				&Error{Field: "slice.1.foo", Msg: "oops"},
+				&Error{Field: "array.0.foo", Msg: "oops"},
+				&Error{Field: "ptr_map.a.foo", Msg: "oops"},
+				&Error{
+					Field: "value_map.a.foo",
+					Code:  CodeRequired,
+					Msg:   "is required",
+				},
+			},
+		},
+		{
+			name: "strict",
+			opts: []Option{WithStrict()},
+			obj:  &obj,
+			wantErrs: []error{
+				&Error{Field: "item.foo", Msg: "oops"},
+				&Error{Field: "slice.1.foo", Msg: "oops"},
+				&Error{Field: "array.0.foo", Msg: "oops"},
+				&Error{Field: "map.a", Err: ErrNotAddressable},
+				&Error{Field: "iface", Err: ErrNotAddressable},
+				&Error{Field: "ptr_map.a.foo", Msg: "oops"},
+				&Error{
+					Field: "value_map.a.foo",
+					Code:  CodeRequired,
+					Msg:   "is required",
+				},
+			},
+		},
+		{
+			name: "top-level value",
+			obj:  testStruct{f: failing},
+			wantErrs: []error{
+				&Error{Field: "foo", Msg: "oops"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := New(tt.opts...).Validate(tt.obj)
+
+			assert.Equal(t, tt.wantErrs, withoutPaths(Errors(err)))
+		})
+	}
+}
+
+func TestValidator_Validate_codes(t *testing.T) {
+	obj := &testNestedStruct{OtherField: &testStruct{
+		f: func() error {
+			errs := Append(nil, MinLength("Foo", "ab", 3))
+
+			return Append(errs, &Error{
+				Path: []PathSegment{{Kind: IndexSegment, Index: 2}},
+				Code: "custom",
+				Msg:  "is custom",
+			})
+		},
+	}}
+
+	err := New().Validate(obj)
+
+	assert.Equal(t, []error{
+		&Error{
+			Field:  "other_field.foo",
+			Code:   CodeTooShort,
+			Params: map[string]interface{}{"min": 3},
+			Msg:    "must be at least 3 characters long",
+		},
+		&Error{Field: "other_field.2", Code: "custom", Msg: "is custom"},
+	}, withoutPaths(Errors(err)))
+}
+
+type testWarningStruct struct {
+	Name  string               `json:"name"`
+	Tag   string               `json:"tag"`
+	Items []*testWarningStruct `json:"items"`
+}
+
+func (s *testWarningStruct) Validate() error {
+	errs := RequireField("Name", s.Name)
+	if s.Tag == "latest" {
+		errs = AppendFieldWarning(errs, "Tag", "is deprecated")
+	}
+	if s.Tag == "" {
+		errs = Append(errs,
+			WithSeverity(RequireField("Tag", s.Tag), SeverityInfo),
+		)
+	}
+
+	return errs
+}
+
+func TestValidator_Check(t *testing.T) {
+	obj := &testWarningStruct{
+		Name: "root",
+		Tag:  "latest",
+		Items: []*testWarningStruct{
+			{Tag: "v1"},
+			{Name: "second"},
+		},
+	}
+
+	res := New().Check(obj)
+
+	assert.False(t, res.Valid())
+	assert.Equal(t, []error{
+		&Error{Field: "items.0.name", Code: CodeRequired, Msg: "is required"},
+	}, withoutPaths(Errors(res.Errors)))
+	assert.Equal(t, []error{
+		&Error{Field: "tag", Severity: SeverityWarning, Msg: "is deprecated"},
+		&Error{
+			Field:    "items.1.tag",
+			Code:     CodeRequired,
+			Severity: SeverityInfo,
+			Msg:      "is required",
+		},
+	}, withoutPaths(Errors(res.Warnings)))
+
+	err := New().Validate(obj)
+
+	assert.Equal(t, res.Errors, err)
+}
+
+func TestValidator_Check_onlyWarnings(t *testing.T) {
+	res := Check(&testWarningStruct{Name: "root", Tag: "latest"})
+
+	assert.True(t, res.Valid())
+	assert.NoError(t, res.Errors)
+	assert.Len(t, Errors(res.Warnings), 1)
+	assert.NoError(t, Validate(&testWarningStruct{Name: "root", Tag: "latest"}))
+}
+
+func TestValidator_Check_maxErrors(t *testing.T) {
+	items := make([]*testWarningStruct, 10)
+	for i := range items {
+		items[i] = &testWarningStruct{Name: "item", Tag: "latest"}
+	}
+	items[7].Name = ""
+
+	res := New(WithFailFast()).Check(items)
+
+	// Warnings of all items before the first error are kept, while the
+	// remainder of the object is not walked once the error limit is reached.
+	assert.Len(t, Errors(res.Errors), 1)
+	assert.Len(t, Errors(res.Warnings), 7)
+}
+
+func TestValidator_CheckContext_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	res := CheckContext(ctx, &testWarningStruct{Tag: "latest"})
+
+	assert.True(t, errors.Is(res.Errors, context.Canceled))
+	assert.NoError(t, res.Warnings)
+}
+
+type testPeriod struct {
+	Start    time.Time  `json:"start_time"`
+	End      *time.Time `json:"end_time"`
+	Password string     `json:"password"`
+	Confirm  string     `json:"password_confirm"`
+	Limits   struct {
+		Min int `yaml:"min_value"`
+		Max int `yaml:"max_value"`
+	} `json:"limits"`
+}
+
+func (s *testPeriod) Validate() error {
+	errs := LessField("Start", s.Start, "End", s.End)
+	errs = Append(errs,
+		EqualField("Confirm", s.Confirm, "Password", s.Password),
+	)
+	errs = Append(errs, WithMessage(
+		NotEqualField("Password", s.Password, "Start", s.Start.String()),
+		"is custom",
+	))
+
+	return Append(errs, GreaterOrEqualField(
+		"Limits.Max", s.Limits.Max, "Limits.Min", s.Limits.Min,
+	))
+}
+
+func TestValidator_Validate_fieldRefs(t *testing.T) {
+	start := time.Date(2021, 8, 22, 10, 0, 0, 0, time.UTC)
+	end := start.Add(-time.Hour)
+	obj := &testPeriod{
+		Start:    start,
+		End:      &end,
+		Password: start.String(),
+		Confirm:  "secret",
+	}
+	obj.Limits.Min = 10
+
+	err := New().Validate([]*testPeriod{obj})
+
+	assert.Equal(t, []error{
+		&Error{
+			Field: "0.start_time",
+			Code:  CodeNotLessField,
+			Params: map[string]interface{}{
+				"other": "end_time", "type": "time",
+			},
+			Msg: "must be before end_time",
+		},
+		&Error{
+			Field:  "0.password_confirm",
+			Code:   CodeNotEqualField,
+			Params: map[string]interface{}{"other": "password"},
+			Msg:    "must be equal to password",
+		},
+		&Error{
+			Field:  "0.password",
+			Code:   CodeEqualField,
+			Params: map[string]interface{}{"other": "start_time"},
+			Msg:    "is custom",
+		},
+		&Error{
+			Field:  "0.limits.max_value",
+			Code:   CodeNotGreaterOrEqualField,
+			Params: map[string]interface{}{"other": "limits.min_value"},
+			Msg:    "must be greater than or equal to limits.min_value",
+		},
+	}, withoutPaths(Errors(err)))
+}
+
+func TestValidator_Validate_fieldRefsTranslated(t *testing.T) {
+	c := NewCatalog()
+	c.Add("de", map[string]string{
+		"not_equal_field": "muss mit {other} übereinstimmen",
+	})
+	obj := &testPeriod{Start: time.Now(), Password: "a", Confirm: "b"}
+	ctx := ContextWithLocale(context.Background(), "de")
+
+	err := New(WithTranslator(c)).ValidateContext(ctx, obj)
+
+	assert.Equal(t, []error{
+		&Error{
+			Field:  "password_confirm",
+			Code:   CodeNotEqualField,
+			Params: map[string]interface{}{"other": "password"},
+			Msg:    "muss mit password übereinstimmen",
+		},
+	}, withoutPaths(Errors(err)))
+}